@@ -0,0 +1,12 @@
+-- Тестовые пользователи для локальной разработки: alice@example.com и bob@example.com,
+-- пароль "password". Не миграция: применяется только явно командой migrate seed-dev.
+INSERT INTO users (id, email, password_hash, full_name) VALUES
+('11111111-1111-1111-1111-111111111111', 'alice@example.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'Alice Smith'),
+('22222222-2222-2222-2222-222222222222', 'bob@example.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'Bob Johnson')
+ON CONFLICT (id) DO NOTHING;
+
+-- Создаем счета для пользователей
+INSERT INTO accounts (id, user_id, account_number, balance) VALUES
+('11111111-1111-1111-1111-111111111111', '11111111-1111-1111-1111-111111111111', '40817810000000000017', 1000000.00),
+('22222222-2222-2222-2222-222222222222', '22222222-2222-2222-2222-222222222222', '40817810000000000025', 1000000.00)
+ON CONFLICT (id) DO NOTHING;
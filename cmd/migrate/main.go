@@ -0,0 +1,118 @@
+// Команда migrate управляет схемой базы:
+//
+//	migrate up           применить все новые миграции
+//	migrate down [N]     откатить N последних миграций (по умолчанию одну)
+//	migrate status       показать примененные и ожидающие миграции
+//	migrate force V      записать версию V без выполнения миграций (после ручного исправления)
+//	migrate seed-dev     добавить тестовых пользователей alice и bob (только для локальной разработки)
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	"money-transfer-service/internal/migrate"
+	"money-transfer-service/pkg/postgres"
+)
+
+// devSeed не входит в цепочку миграций: известный пароль тестовых пользователей
+// не должен попасть ни в одно окружение, кроме локального
+//
+//go:embed dev_seed.sql
+var devSeed string
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | down [N] | status | force VERSION | seed-dev")
+	os.Exit(2)
+}
+
+func main() {
+	_ = godotenv.Load()
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	db, err := postgres.Connect(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := migrate.New(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "up":
+		n, err := m.Up(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Applied %d migration(s)\n", n)
+
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				usage()
+			}
+		}
+		n, err := m.Down(ctx, steps)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Reverted %d migration(s)\n", n)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			switch {
+			case s.Dirty:
+				state = "DIRTY"
+			case s.Applied && s.AppliedAt != nil:
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			case s.Applied:
+				state = "applied"
+			}
+			fmt.Printf("%04d  %-30s  %s\n", s.Version, s.Name, state)
+		}
+
+	case "force":
+		if len(os.Args) < 3 {
+			usage()
+		}
+		v, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err != nil || v < 0 {
+			usage()
+		}
+		if err := m.Force(ctx, v); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Schema version set to %d\n", v)
+
+	case "seed-dev":
+		if err := m.Check(ctx); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := db.ExecContext(ctx, devSeed); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Seeded development users alice@example.com and bob@example.com")
+
+	default:
+		usage()
+	}
+}
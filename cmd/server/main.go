@@ -1,80 +1,207 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/go-chi/chi"
-	"github.com/joho/godotenv"
+	"money-transfer-service/internal/auth"
 	"money-transfer-service/internal/cache"
+	"money-transfer-service/internal/config"
+	"money-transfer-service/internal/events"
+	"money-transfer-service/internal/grpcserver"
 	"money-transfer-service/internal/handler"
-	"money-transfer-service/internal/middleware"
+	"money-transfer-service/internal/logging"
+	"money-transfer-service/internal/metrics"
+	"money-transfer-service/internal/migrate"
+	"money-transfer-service/internal/models"
 	"money-transfer-service/internal/repository"
 	"money-transfer-service/internal/service"
+	"money-transfer-service/internal/tracing"
+	"money-transfer-service/internal/validation"
+	"money-transfer-service/internal/webhook"
 	"money-transfer-service/pkg/postgres"
 )
 
 func main() {
-	_ = godotenv.Load()
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to YAML config file")
+	flag.Parse()
 
-	db, err := postgres.Connect(os.Getenv("DATABASE_URL"))
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatal(err)
+		fatal("invalid configuration", err)
+	}
+	logging.Setup(cfg.Log)
+	slog.Info("configuration loaded", "config", cfg.String())
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
+
+	// Принимаем только валюты, для которых есть курс к RUB
+	currencies := []string{"RUB"}
+	for currency := range cfg.FX.Rates {
+		currencies = append(currencies, currency)
+	}
+	if err := validation.SetSupportedCurrencies(currencies); err != nil {
+		fatal("invalid currency list", err)
+	}
+
+	auth.JWTSecret = []byte(cfg.JWT.Secret.Value())
+	auth.TokenTTL = cfg.JWT.TTL
+
+	db, err := postgres.Connect(cfg.DB.URL.Value())
+	if err != nil {
+		fatal("failed to connect to database", err)
 	}
 	defer db.Close()
+	db.SetMaxOpenConns(cfg.DB.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.DB.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DB.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.DB.ConnMaxIdleTime)
+	metrics.RegisterDB(db, "postgres")
 
-	redisClient := cache.NewRedisClient(os.Getenv("REDIS_ADDR"))
+	// Сервер работает только со схемой той версии, под которую собран.
+	// AUTO_MIGRATE=true применяет недостающие миграции при старте.
+	migrator, err := migrate.New(db)
+	if err != nil {
+		fatal("failed to load migrations", err)
+	}
+	if cfg.DB.AutoMigrate {
+		if _, err := migrator.Up(context.Background()); err != nil {
+			fatal("failed to apply migrations", err)
+		}
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		fatal("database schema is out of date, run `go run ./cmd/migrate up`", err)
+	}
+
+	redisClient := cache.NewRedisClient(cfg.Redis.Addr, cfg.Redis.Password.Value(), cfg.Redis.DB)
 
 	repo := repository.NewRepository(db)
-	serv := service.NewService(repo, redisClient)
-	h := handler.NewHandler(serv)
-	authHandler := handler.NewAuthHandler(repo)
-
-	// Создаем роутер
-	r := chi.NewRouter()
-
-	// Middleware логгирования (должно быть первым)
-	r.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			log.Printf("Request: %s %s", r.Method, r.URL.Path)
-			next.ServeHTTP(w, r)
-		})
+	repo.SetDailyTransferLimit(cfg.Limits.DailyTransfer)
+	serv := service.NewService(repo, redisClient, cfg)
+	h := handler.NewHandler(serv, cfg.HTTP.PublicBaseURL)
+	authHandler := handler.NewAuthHandler(serv)
+
+	health := handler.NewHealthHandler(db, redisClient, migrator)
+
+	// Фоновые задачи останавливаются после того, как HTTP-сервер дождется текущих запросов,
+	// чтобы события от последних переводов успели уйти из outbox
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	background := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
+	}
+
+	// Фоновое завершение сделок с истекшим дедлайном и снятие просроченных удержаний
+	background(func(ctx context.Context) { serv.RunHoldWorker(ctx, time.Minute) })
+
+	// Пакетные переводы, включая не завершенные до перезапуска
+	background(func(ctx context.Context) { serv.RunBatchWorker(ctx, 10*time.Second) })
+
+	// Обновления в открытые сессии пользователей
+	background(serv.RunRealtime)
+
+	// Доставка вебхуков с повторами
+	background(func(ctx context.Context) { webhook.NewDispatcher(repo).Run(ctx, 5*time.Second) })
+
+	// Доменные события: outbox -> шина. EVENT_BUS=redis — Redis Streams, иначе внутри процесса
+	var bus events.Bus = events.NewMemoryBus(repo)
+	if cfg.Events.Bus == "redis" {
+		bus = events.NewRedisStreamBus(redisClient.Client())
+	}
+	subscribe := func(consumer string, handler events.Handler) {
+		background(func(ctx context.Context) { bus.Subscribe(ctx, consumer, handler) })
+	}
+	background(func(ctx context.Context) { events.NewRelay(repo, bus).Run(ctx, time.Second) })
+	subscribe("realtime", serv.PublishRealtimeEvent)
+	subscribe("notifications", serv.HandleNotificationEvent)
+	subscribe("event-log", func(ctx context.Context, e models.DomainEvent) error {
+		slog.InfoContext(ctx, "event", "sequence", e.Sequence, "type", e.Type,
+			"aggregate_type", e.AggregateType, "aggregate_id", e.AggregateID)
+		return nil
 	})
 
-	// Serve static files
 	workDir, _ := os.Getwd()
-	filesDir := http.Dir(filepath.Join(workDir, "static"))
-	fileServer := http.FileServer(filesDir)
+	r := h.Routes(authHandler, health, repo, filepath.Join(workDir, "static"))
+
+	srv := &http.Server{
+		Addr:         cfg.HTTP.Addr,
+		Handler:      r,
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+		IdleTimeout:  cfg.HTTP.IdleTimeout,
+	}
+	srv.RegisterOnShutdown(h.Shutdown)
 
-	// Статические файлы
-	r.Handle("/static/*", http.StripPrefix("/static/", fileServer))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Главная страница
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/" {
-			http.ServeFile(w, r, filepath.Join(workDir, "static", "index.html"))
-			return
+	go func() {
+		slog.Info("server starting", "addr", cfg.HTTP.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("server failed", err)
 		}
-		fileServer.ServeHTTP(w, r)
-	})
+	}()
 
-	// Public routes
-	r.Post("/auth/register", authHandler.Register)
-	r.Post("/auth/login", authHandler.Login)
+	// gRPC для внутренних сервисов — рядом с REST, на своем порту
+	var grpcSrv *grpcserver.Server
+	if cfg.GRPC.Addr != "" {
+		grpcSrv, err = grpcserver.New(serv, repo, cfg.GRPC.APIKeys)
+		if err != nil {
+			fatal("failed to create grpc server", err)
+		}
+		lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			fatal("failed to listen for grpc", err)
+		}
+		go func() {
+			slog.Info("grpc server starting", "addr", cfg.GRPC.Addr)
+			if err := grpcSrv.Serve(lis); err != nil {
+				fatal("grpc server failed", err)
+			}
+		}()
+	}
 
-	// Protected routes - создаем подроутер с middleware аутентификации
-	r.Route("/api", func(r chi.Router) {
-		// Middleware аутентификации только для API routes
-		r.Use(middleware.AuthMiddleware(repo))
+	<-ctx.Done()
+	// Повторный сигнал завершит процесс сразу
+	stop()
 
-		r.Get("/balance", h.GetBalance)
-		r.Post("/transfer", h.TransferMoney)
-		r.Post("/deposit", h.DepositMoney)
-		r.Get("/transfers", h.GetTransfersHistory)
-	})
+	slog.Info("shutting down, waiting for in-flight requests", "timeout", cfg.HTTP.ShutdownTimeout)
+	health.SetDraining()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server shutdown failed", "error", err)
+	}
+	if grpcSrv != nil {
+		grpcSrv.Shutdown(shutdownCtx)
+	}
+
+	stopWorkers()
+	workers.Wait()
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("tracing shutdown failed", "error", err)
+	}
+	slog.Info("server stopped")
+}
 
-	log.Println("Server starting on :8080")
-	log.Fatal(http.ListenAndServe(":8080", r))
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
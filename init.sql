@@ -1,37 +0,0 @@
--- Создаем таблицу пользователей
-CREATE TABLE IF NOT EXISTS users (
-    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    email VARCHAR(255) UNIQUE NOT NULL,
-    password_hash VARCHAR(255) NOT NULL,
-    full_name VARCHAR(255) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
--- Создаем таблицу счетов
-CREATE TABLE IF NOT EXISTS accounts (
-    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    user_id UUID REFERENCES users(id),
-    balance DECIMAL(15, 2) DEFAULT 0.00
-);
-
--- Создаем таблицу переводов
-CREATE TABLE IF NOT EXISTS transfers (
-    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    from_account_id UUID REFERENCES accounts(id),
-    to_account_id UUID REFERENCES accounts(id),
-    amount DECIMAL(15, 2) NOT NULL,
-    currency VARCHAR(3) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
--- Вставляем тестовых пользователей
-INSERT INTO users (id, email, password_hash, full_name) VALUES
-('11111111-1111-1111-1111-111111111111', 'alice@example.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'Alice Smith'),
-('22222222-2222-2222-2222-222222222222', 'bob@example.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'Bob Johnson')
-ON CONFLICT (id) DO NOTHING;
-
--- Создаем счета для пользователей
-INSERT INTO accounts (id, user_id, balance) VALUES
-('11111111-1111-1111-1111-111111111111', '11111111-1111-1111-1111-111111111111', 1000000.00),
-('22222222-2222-2222-2222-222222222222', '22222222-2222-2222-2222-222222222222', 1000000.00)
-ON CONFLICT (id) DO NOTHING;
\ No newline at end of file
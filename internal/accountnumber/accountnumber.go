@@ -0,0 +1,80 @@
+// Package accountnumber генерирует и проверяет 20-значные номера счетов
+// в формате 40817 810 X XXXX XXXXXXX с контрольной цифрой по алгоритму Луна.
+package accountnumber
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	Length = 20
+
+	// Балансовый счет физлица (40817) и код валюты RUB (810)
+	prefix = "40817810"
+)
+
+var ErrInvalid = errors.New("invalid account number")
+
+// Generate возвращает случайный номер счета с корректной контрольной цифрой
+func Generate() (string, error) {
+	bodyLength := Length - len(prefix) - 1
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(bodyLength)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate account number: %w", err)
+	}
+
+	body := prefix + fmt.Sprintf("%0*d", bodyLength, n)
+	return body + string(checkDigit(body)), nil
+}
+
+// Normalize убирает пробелы и дефисы, которыми номер обычно разбивают на группы
+func Normalize(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(number))
+}
+
+// Validate проверяет длину, состав и контрольную цифру нормализованного номера
+func Validate(number string) error {
+	if len(number) != Length {
+		return fmt.Errorf("%w: must be %d digits", ErrInvalid, Length)
+	}
+	for _, c := range number {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("%w: must contain only digits", ErrInvalid)
+		}
+	}
+	if checkDigit(number[:Length-1]) != rune(number[Length-1]) {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalid)
+	}
+	return nil
+}
+
+// Format разбивает номер на группы для отображения: 40817 810 0 1234 5678901
+func Format(number string) string {
+	if len(number) != Length {
+		return number
+	}
+	return strings.Join([]string{number[:5], number[5:8], number[8:9], number[9:13], number[13:]}, " ")
+}
+
+// checkDigit считает контрольную цифру Луна для номера без нее
+func checkDigit(body string) rune {
+	sum := 0
+	double := true
+	for i := len(body) - 1; i >= 0; i-- {
+		d := int(body[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return rune('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,160 @@
+// Package alias сопоставляет получателям переводов их псевдонимы: email,
+// номер телефона, имя пользователя или номер счета. Новые типы псевдонимов
+// подключаются через Directory.Register.
+package alias
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"money-transfer-service/internal/accountnumber"
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+)
+
+var (
+	ErrUnknownType = errors.New("unknown alias type")
+	ErrInvalid     = errors.New("invalid alias value")
+)
+
+// Resolver умеет нормализовать значение псевдонима своего типа и найти по нему счет.
+// Resolve возвращает nil, nil, если получатель не найден.
+type Resolver interface {
+	Type() string
+	Normalize(value string) (string, error)
+	Resolve(ctx context.Context, value string) (*models.Account, error)
+}
+
+type Directory struct {
+	resolvers map[string]Resolver
+}
+
+// NewDirectory создает справочник со встроенными типами псевдонимов
+func NewDirectory(repo *repository.Repository) *Directory {
+	d := &Directory{resolvers: make(map[string]Resolver)}
+	d.Register(emailResolver{repo: repo})
+	d.Register(accountNumberResolver{repo: repo})
+	d.Register(registeredResolver{aliasType: models.AliasPhone, normalize: NormalizePhone, repo: repo})
+	d.Register(registeredResolver{aliasType: models.AliasUsername, normalize: NormalizeUsername, repo: repo})
+	return d
+}
+
+func (d *Directory) Register(r Resolver) {
+	d.resolvers[r.Type()] = r
+}
+
+func (d *Directory) Normalize(aliasType, value string) (string, error) {
+	r, ok := d.resolvers[aliasType]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownType, aliasType)
+	}
+	return r.Normalize(value)
+}
+
+// Resolve находит счет получателя по псевдониму
+func (d *Directory) Resolve(ctx context.Context, recipient models.Recipient) (*models.Account, error) {
+	r, ok := d.resolvers[recipient.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, recipient.Type)
+	}
+
+	value, err := r.Normalize(recipient.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := r.Resolve(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("recipient %w for %s: %s", repository.ErrAccountNotFound, recipient.Type, value)
+	}
+	return account, nil
+}
+
+var (
+	phonePattern    = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	usernamePattern = regexp.MustCompile(`^[a-z0-9_.]{3,32}$`)
+)
+
+// NormalizePhone приводит номер к формату E.164. Российские номера вида 8XXXXXXXXXX
+// переводятся в +7XXXXXXXXXX.
+func NormalizePhone(value string) (string, error) {
+	phone := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(strings.TrimSpace(value))
+	if len(phone) == 11 && strings.HasPrefix(phone, "8") {
+		phone = "+7" + phone[1:]
+	}
+	if !phonePattern.MatchString(phone) {
+		return "", fmt.Errorf("%w: phone must be in E.164 format, e.g. +79991234567", ErrInvalid)
+	}
+	return phone, nil
+}
+
+func NormalizeUsername(value string) (string, error) {
+	username := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "@"))
+	if !usernamePattern.MatchString(username) {
+		return "", fmt.Errorf("%w: username must be 3-32 characters of a-z, 0-9, _ and .", ErrInvalid)
+	}
+	return username, nil
+}
+
+type emailResolver struct {
+	repo *repository.Repository
+}
+
+func (emailResolver) Type() string { return models.AliasEmail }
+
+func (emailResolver) Normalize(value string) (string, error) {
+	email := strings.TrimSpace(value)
+	if !strings.Contains(email, "@") {
+		return "", fmt.Errorf("%w: invalid email", ErrInvalid)
+	}
+	return email, nil
+}
+
+func (r emailResolver) Resolve(ctx context.Context, value string) (*models.Account, error) {
+	return r.repo.GetAccountByEmail(ctx, value)
+}
+
+type accountNumberResolver struct {
+	repo *repository.Repository
+}
+
+func (accountNumberResolver) Type() string { return models.AliasAccountNumber }
+
+func (accountNumberResolver) Normalize(value string) (string, error) {
+	number := accountnumber.Normalize(value)
+	if err := accountnumber.Validate(number); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return number, nil
+}
+
+func (r accountNumberResolver) Resolve(ctx context.Context, value string) (*models.Account, error) {
+	return r.repo.GetAccountByNumber(ctx, value)
+}
+
+// registeredResolver ищет получателя среди подтвержденных псевдонимов пользователей
+type registeredResolver struct {
+	aliasType string
+	normalize func(string) (string, error)
+	repo      *repository.Repository
+}
+
+func (r registeredResolver) Type() string { return r.aliasType }
+
+func (r registeredResolver) Normalize(value string) (string, error) {
+	return r.normalize(value)
+}
+
+func (r registeredResolver) Resolve(ctx context.Context, value string) (*models.Account, error) {
+	a, err := r.repo.GetVerifiedAlias(ctx, r.aliasType, value)
+	if err != nil || a == nil {
+		return nil, err
+	}
+	return r.repo.GetAccountByUserID(ctx, a.UserID)
+}
@@ -0,0 +1,58 @@
+package alias
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/notify"
+)
+
+// CodeSender доставляет код подтверждения владельцу псевдонима (SMS, email и т.п.)
+type CodeSender interface {
+	SendCode(ctx context.Context, aliasType, value, code string) error
+}
+
+// SMSCodeSender отправляет коды подтверждения телефонов по SMS
+type SMSCodeSender struct {
+	SMS notify.Notifier
+}
+
+func (s SMSCodeSender) SendCode(ctx context.Context, aliasType, value, code string) error {
+	if aliasType != models.AliasPhone {
+		return fmt.Errorf("no channel for %s verification codes", aliasType)
+	}
+	return s.SMS.Send(ctx, notify.Message{
+		To:   value,
+		Body: fmt.Sprintf("Код подтверждения: %s\nVerification code: %s", code, code),
+	})
+}
+
+// LogCodeSender пишет коды в лог вместо отправки. Создается только при APP_ENV=development:
+// ключ dev_code намеренно не входит в список скрываемых, чтобы код можно было прочитать.
+type LogCodeSender struct{}
+
+func (LogCodeSender) SendCode(ctx context.Context, aliasType, value, code string) error {
+	slog.InfoContext(ctx, "verification code", "alias_type", aliasType, "alias", value, "dev_code", code)
+	return nil
+}
+
+// GenerateCode возвращает случайный шестизначный код
+func GenerateCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n), nil
+}
+
+// HashCode — в базе хранится только хэш кода
+func HashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,15 +1,23 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/tracing"
 )
 
-var JWTSecret = []byte("your-secret-key") // В продакшене используйте переменные окружения
+// JWTSecret и TokenTTL задаются из конфигурации при старте
+var (
+	JWTSecret []byte
+	TokenTTL  = 72 * time.Hour
+)
 
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -21,11 +29,19 @@ func CheckPasswordHash(password, hash string) bool {
 	return err == nil
 }
 
+// CheckPassword — CheckPasswordHash с отдельным спаном: bcrypt намеренно медленный
+// и заметен в трейсе запроса
+func CheckPassword(ctx context.Context, password, hash string) bool {
+	_, span := tracing.Start(ctx, "auth.CheckPassword")
+	defer span.End()
+	return CheckPasswordHash(password, hash)
+}
+
 func GenerateJWT(user models.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID.String(),
 		"email":   user.Email,
-		"exp":     time.Now().Add(time.Hour * 72).Unix(),
+		"exp":     time.Now().Add(TokenTTL).Unix(),
 	})
 
 	return token.SignedString(JWTSecret)
@@ -39,3 +55,26 @@ func ParseJWT(tokenString string) (*jwt.Token, error) {
 		return JWTSecret, nil
 	})
 }
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// UserIDFromToken проверяет подпись и срок действия JWT и возвращает пользователя из него
+func UserIDFromToken(tokenString string) (uuid.UUID, error) {
+	token, err := ParseJWT(tokenString)
+	if err != nil || !token.Valid {
+		return uuid.Nil, ErrInvalidToken
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return uuid.Nil, ErrInvalidToken
+	}
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return uuid.Nil, ErrInvalidToken
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return id, nil
+}
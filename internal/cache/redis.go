@@ -12,12 +12,13 @@ type RedisClient struct {
 	client *redis.Client
 }
 
-func NewRedisClient(addr string) *RedisClient {
+func NewRedisClient(addr, password string, db int) *RedisClient {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     addr,
-		Password: "", // no password set
-		DB:       0,  // use default DB
+		Password: password,
+		DB:       db,
 	})
+	rdb.AddHook(tracingHook{})
 	return &RedisClient{client: rdb}
 }
 
@@ -34,3 +35,12 @@ func (rc *RedisClient) Get(ctx context.Context, key string) (string, error) {
 func (rc *RedisClient) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
 	return rc.client.Set(ctx, key, value, expiration).Err()
 }
+
+// Client — низкоуровневый клиент для потоков и pub/sub
+func (rc *RedisClient) Client() *redis.Client {
+	return rc.client
+}
+
+func (rc *RedisClient) Ping(ctx context.Context) error {
+	return rc.client.Ping(ctx).Err()
+}
@@ -0,0 +1,372 @@
+// Package config собирает настройки сервиса в одну проверенную структуру.
+// Источники по возрастанию приоритета: значения по умолчанию, YAML-файл, .env, переменные окружения.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+
+	"money-transfer-service/internal/validation"
+)
+
+type Config struct {
+	// Env — production или development. Только development разрешает заглушки,
+	// которые пишут секреты в лог (например, коды подтверждения телефонов).
+	Env     string        `yaml:"env" env:"APP_ENV"`
+	HTTP    HTTPConfig    `yaml:"http"`
+	GRPC    GRPCConfig    `yaml:"grpc"`
+	DB      DBConfig      `yaml:"db"`
+	Redis   RedisConfig   `yaml:"redis"`
+	JWT     JWTConfig     `yaml:"jwt"`
+	FX      FXConfig      `yaml:"fx"`
+	Limits  LimitsConfig  `yaml:"limits"`
+	Events  EventsConfig  `yaml:"events"`
+	Notify  NotifyConfig  `yaml:"notify"`
+	Tracing TracingConfig `yaml:"tracing"`
+	Log     LogConfig     `yaml:"log"`
+}
+
+type HTTPConfig struct {
+	Addr         string        `yaml:"addr" env:"HTTP_ADDR"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	// ShutdownTimeout — сколько ждать завершения текущих запросов при остановке
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT"`
+	// PublicBaseURL — внешний адрес сервиса, используется в ссылках на оплату и QR-кодах.
+	// Заголовок Host клиента для этого не годится: его можно подделать.
+	PublicBaseURL string `yaml:"public_base_url" env:"PUBLIC_BASE_URL"`
+}
+
+type GRPCConfig struct {
+	// Addr — адрес gRPC-сервера; пустой отключает его
+	Addr string `yaml:"addr" env:"GRPC_ADDR"`
+	// APIKeys — ключи внутренних сервисов. В окружении: GRPC_API_KEYS=name:user_id:key,...
+	APIKeys APIKeys `yaml:"api_keys" env:"GRPC_API_KEYS"`
+}
+
+// APIKey действует от имени пользователя UserID (сервисной учетной записи)
+type APIKey struct {
+	Name   string `yaml:"name"`
+	UserID string `yaml:"user_id"`
+	Key    Secret `yaml:"key"`
+}
+
+type APIKeys []APIKey
+
+func (k *APIKeys) UnmarshalEnv(raw string) error {
+	var keys APIKeys
+	for _, entry := range strings.Split(raw, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid API key entry, expected name:user_id:key")
+		}
+		keys = append(keys, APIKey{Name: parts[0], UserID: parts[1], Key: Secret(parts[2])})
+	}
+	*k = keys
+	return nil
+}
+
+type DBConfig struct {
+	URL             Secret        `yaml:"url" env:"DATABASE_URL"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+	// AutoMigrate применяет недостающие миграции при старте сервера
+	AutoMigrate bool `yaml:"auto_migrate" env:"AUTO_MIGRATE"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr" env:"REDIS_ADDR"`
+	Password Secret `yaml:"password" env:"REDIS_PASSWORD"`
+	DB       int    `yaml:"db" env:"REDIS_DB"`
+}
+
+type JWTConfig struct {
+	Secret Secret        `yaml:"secret" env:"JWT_SECRET"`
+	TTL    time.Duration `yaml:"ttl" env:"JWT_TTL"`
+}
+
+type FXConfig struct {
+	// Rates — курс 1 единицы валюты к RUB. В окружении: FX_RATES=USD=90,EUR=100
+	Rates    map[string]float64 `yaml:"rates" env:"FX_RATES"`
+	CacheTTL time.Duration      `yaml:"cache_ttl" env:"FX_CACHE_TTL"`
+}
+
+// LimitsConfig — суммы в RUB
+type LimitsConfig struct {
+	// DailyTransfer — сумма списаний счета по инициативе клиента за сутки (переводы, оплаты,
+	// удержания); расчеты по уже удержанным средствам в нее не входят. 0 — без лимита
+	DailyTransfer float64 `yaml:"daily_transfer" env:"LIMIT_DAILY_TRANSFER"`
+	// StepUpThreshold — переводы новым получателям от этой суммы требуют повторного ввода пароля
+	StepUpThreshold float64 `yaml:"step_up_threshold" env:"LIMIT_STEP_UP_THRESHOLD"`
+}
+
+type EventsConfig struct {
+	// Bus — memory (внутри процесса) или redis (Redis Streams)
+	Bus string `yaml:"bus" env:"EVENT_BUS"`
+}
+
+type NotifyConfig struct {
+	SMTPAddr     string `yaml:"smtp_addr" env:"SMTP_ADDR"`
+	SMTPFrom     string `yaml:"smtp_from" env:"SMTP_FROM"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword Secret `yaml:"smtp_password" env:"SMTP_PASSWORD"`
+	// File — запись уведомлений в файл вместо лога
+	File string `yaml:"file" env:"NOTIFY_FILE"`
+	// SMSGatewayURL — HTTP-шлюз SMS-провайдера; без него SMS не отправляются
+	SMSGatewayURL   string `yaml:"sms_gateway_url" env:"SMS_GATEWAY_URL"`
+	SMSGatewayToken Secret `yaml:"sms_gateway_token" env:"SMS_GATEWAY_TOKEN"`
+}
+
+type TracingConfig struct {
+	// Exporter — none, stdout (для локальной отладки) или otlp
+	Exporter     string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OTLPInsecure bool    `yaml:"otlp_insecure" env:"OTEL_EXPORTER_OTLP_INSECURE"`
+	ServiceName  string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio  float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+type LogConfig struct {
+	// Level — debug, info, warn или error
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+	// Format — json для продакшена или text для чтения глазами
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+const (
+	EnvProduction  = "production"
+	EnvDevelopment = "development"
+)
+
+// Development — локальная разработка: допускаются заглушки вместо внешних сервисов
+func (c Config) Development() bool {
+	return c.Env == EnvDevelopment
+}
+
+// Default — значения для локальной разработки. Секреты по умолчанию не задаются.
+func Default() Config {
+	return Config{
+		Env: EnvProduction,
+		HTTP: HTTPConfig{
+			Addr:            ":8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			PublicBaseURL:   "http://localhost:8080",
+		},
+		GRPC: GRPCConfig{Addr: ":9090"},
+		DB: DBConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Redis: RedisConfig{Addr: "localhost:6379"},
+		JWT:   JWTConfig{TTL: 72 * time.Hour},
+		FX: FXConfig{
+			Rates:    map[string]float64{"USD": 90.0, "EUR": 100.0},
+			CacheTTL: 5 * time.Minute,
+		},
+		Limits: LimitsConfig{
+			StepUpThreshold: 10000.0,
+		},
+		Events: EventsConfig{Bus: "memory"},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "localhost:4318",
+			ServiceName:  "money-transfer-service",
+			SampleRatio:  1,
+		},
+		Log: LogConfig{Level: "info", Format: "json"},
+	}
+}
+
+// Load читает конфигурацию. path — необязательный YAML-файл; .env в рабочем каталоге
+// подхватывается, если есть, и не перекрывает уже заданные переменные окружения.
+// Все ошибки разбора и проверки возвращаются одним списком.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+
+	var errs Errors
+	errs = append(errs, applyEnv(&cfg)...)
+	errs = append(errs, cfg.Validate()...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &cfg, nil
+}
+
+// Errors — все найденные проблемы конфигурации
+type Errors []error
+
+func (e Errors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = "  - " + err.Error()
+	}
+	return "invalid configuration:\n" + strings.Join(lines, "\n")
+}
+
+// Validate проверяет значения и возвращает все ошибки сразу
+func (c *Config) Validate() Errors {
+	var errs Errors
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Env != EnvProduction && c.Env != EnvDevelopment {
+		add("env (APP_ENV) must be %s or %s, got %q", EnvProduction, EnvDevelopment, c.Env)
+	}
+
+	if c.HTTP.Addr == "" {
+		add("http.addr (HTTP_ADDR) is required")
+	}
+	if c.HTTP.ReadTimeout <= 0 || c.HTTP.WriteTimeout <= 0 || c.HTTP.IdleTimeout <= 0 || c.HTTP.ShutdownTimeout <= 0 {
+		add("http timeouts must be positive")
+	}
+	if !strings.HasPrefix(c.HTTP.PublicBaseURL, "http://") && !strings.HasPrefix(c.HTTP.PublicBaseURL, "https://") {
+		add("http.public_base_url (PUBLIC_BASE_URL) is required and must be an http(s) URL")
+	}
+
+	names := map[string]bool{}
+	keys := map[Secret]bool{}
+	for i, k := range c.GRPC.APIKeys {
+		switch {
+		case k.Name == "":
+			add("grpc.api_keys[%d]: name is required", i)
+		case names[k.Name]:
+			add("grpc.api_keys: duplicate name %q", k.Name)
+		}
+		names[k.Name] = true
+		if _, err := uuid.Parse(k.UserID); err != nil {
+			add("grpc.api_keys[%d]: user_id must be a UUID", i)
+		}
+		if len(k.Key) < 32 {
+			add("grpc.api_keys[%d]: key must be at least 32 characters", i)
+		} else if keys[k.Key] {
+			add("grpc.api_keys[%d]: key is already used by another entry", i)
+		}
+		keys[k.Key] = true
+	}
+
+	if c.DB.URL == "" {
+		add("db.url (DATABASE_URL) is required")
+	}
+	if c.DB.MaxOpenConns < 0 || c.DB.MaxIdleConns < 0 {
+		add("db connection pool sizes must not be negative")
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		add("db.max_idle_conns (%d) must not exceed db.max_open_conns (%d)", c.DB.MaxIdleConns, c.DB.MaxOpenConns)
+	}
+
+	if c.Redis.Addr == "" {
+		add("redis.addr (REDIS_ADDR) is required")
+	}
+	if c.Redis.DB < 0 {
+		add("redis.db (REDIS_DB) must not be negative")
+	}
+
+	if c.JWT.Secret == "" {
+		add("jwt.secret (JWT_SECRET) is required")
+	} else if len(c.JWT.Secret) < 32 {
+		add("jwt.secret (JWT_SECRET) must be at least 32 characters")
+	}
+	if c.JWT.TTL <= 0 {
+		add("jwt.ttl (JWT_TTL) must be positive")
+	}
+
+	for currency, rate := range c.FX.Rates {
+		switch {
+		case currency == "RUB":
+			add("fx.rates: RUB is the base currency and must not be listed")
+		case !validation.IsISO4217(currency):
+			add("fx.rates: %q is not an ISO 4217 currency code", currency)
+		}
+		if rate <= 0 {
+			add("fx.rates: rate for %s must be positive", currency)
+		}
+	}
+	if c.FX.CacheTTL <= 0 {
+		add("fx.cache_ttl (FX_CACHE_TTL) must be positive")
+	}
+
+	if c.Limits.DailyTransfer < 0 {
+		add("limits.daily_transfer (LIMIT_DAILY_TRANSFER) must not be negative")
+	}
+	if c.Limits.StepUpThreshold <= 0 {
+		add("limits.step_up_threshold (LIMIT_STEP_UP_THRESHOLD) must be positive")
+	}
+
+	if c.Events.Bus != "memory" && c.Events.Bus != "redis" {
+		add("events.bus (EVENT_BUS) must be memory or redis, got %q", c.Events.Bus)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Tracing.OTLPEndpoint == "" {
+			add("tracing.otlp_endpoint (OTEL_EXPORTER_OTLP_ENDPOINT) is required for the otlp exporter")
+		}
+	default:
+		add("tracing.exporter (TRACING_EXPORTER) must be none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1")
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		add("log.level (LOG_LEVEL) must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		add("log.format (LOG_FORMAT) must be json or text, got %q", c.Log.Format)
+	}
+
+	if c.Notify.SMTPAddr != "" && c.Notify.SMTPFrom == "" {
+		add("notify.smtp_from (SMTP_FROM) is required when SMTP is enabled")
+	}
+	if c.Notify.SMSGatewayURL != "" {
+		if u, err := url.Parse(c.Notify.SMSGatewayURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("notify.sms_gateway_url (SMS_GATEWAY_URL) must be an absolute http or https URL")
+		}
+	}
+	return errs
+}
+
+// String выводит конфигурацию в YAML с замаскированными секретами
+func (c Config) String() string {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Secret — значение, которое не должно попадать в логи. При печати и сериализации маскируется.
+type Secret string
+
+const masked = "******"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return masked
+}
+
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(s.String())), nil
+}
+
+// Value возвращает само значение секрета
+func (s Secret) Value() string {
+	return string(s)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// envUnmarshaler разбирает значение переменной окружения в поле сложного типа
+type envUnmarshaler interface {
+	UnmarshalEnv(raw string) error
+}
+
+// applyEnv перекрывает поля с тегом env значениями переменных окружения
+func applyEnv(cfg *Config) Errors {
+	var errs Errors
+	walk(reflect.ValueOf(cfg).Elem(), &errs)
+	return errs
+}
+
+func walk(v reflect.Value, errs *Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct && field.Type() != durationType {
+			walk(field, errs)
+			continue
+		}
+
+		name := t.Field(i).Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setField(field, strings.TrimSpace(raw)); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+}
+
+func setField(field reflect.Value, raw string) error {
+	if u, ok := field.Addr().Interface().(envUnmarshaler); ok {
+		return u.UnmarshalEnv(raw)
+	}
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		field.SetBool(b)
+	case reflect.Map:
+		// Формат KEY=VALUE,KEY=VALUE; поддерживаются только map[string]float64
+		rates := make(map[string]float64)
+		for _, pair := range strings.Split(raw, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return fmt.Errorf("invalid pair %q, expected KEY=VALUE", pair)
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", value)
+			}
+			rates[strings.ToUpper(strings.TrimSpace(key))] = f
+		}
+		field.Set(reflect.ValueOf(rates))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Package events доставляет доменные события из outbox подписчикам: уведомлениям,
+// вебхукам, аналитике. Доставка как минимум однократная, обработчики должны быть идемпотентны.
+package events
+
+import (
+	"context"
+
+	"money-transfer-service/internal/models"
+)
+
+// Handler обрабатывает одно событие. Ошибка означает, что событие нужно доставить повторно.
+type Handler func(ctx context.Context, event models.DomainEvent) error
+
+// Publisher публикует события, прочитанные relay из outbox
+type Publisher interface {
+	Publish(ctx context.Context, event models.DomainEvent) error
+}
+
+// Subscriber доставляет события потребителю consumer, начиная со смещения, на котором он
+// остановился. Блокируется до отмены контекста.
+type Subscriber interface {
+	Subscribe(ctx context.Context, consumer string, handler Handler) error
+}
+
+// Bus — шина событий: и публикация, и подписка
+type Bus interface {
+	Publisher
+	Subscriber
+}
+
+// Store — журнал опубликованных событий и смещения потребителей (таблица outbox)
+type Store interface {
+	GetPublishedEvents(ctx context.Context, afterSequence int64, limit int) ([]models.DomainEvent, error)
+	GetConsumerOffset(ctx context.Context, consumer string) (int64, error)
+	SaveConsumerOffset(ctx context.Context, consumer string, sequence int64) error
+}
+
+// committer — необязательный интерфейс Publisher: relay сообщает, что пачка событий зафиксирована
+type committer interface {
+	Committed()
+}
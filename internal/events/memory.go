@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"money-transfer-service/internal/logging"
+	"money-transfer-service/internal/models"
+)
+
+const (
+	memoryBatchSize    = 100
+	memoryPollInterval = 5 * time.Second
+	retryDelay         = 5 * time.Second
+)
+
+// MemoryBus — шина внутри процесса. Сами события не хранятся в памяти: потребитель читает
+// журнал outbox начиная со своего смещения, а Publish лишь будит потребителей. Поэтому
+// события не теряются при перезапуске и при подписке позже публикации.
+type MemoryBus struct {
+	store Store
+
+	mu      sync.Mutex
+	waiters map[chan struct{}]struct{}
+}
+
+func NewMemoryBus(store Store) *MemoryBus {
+	return &MemoryBus{store: store, waiters: make(map[chan struct{}]struct{})}
+}
+
+// Publish ничего не делает: событие уже лежит в outbox и станет видно после фиксации
+func (b *MemoryBus) Publish(ctx context.Context, event models.DomainEvent) error {
+	return nil
+}
+
+// Committed будит всех потребителей
+func (b *MemoryBus) Committed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.waiters {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context, consumer string, handler Handler) error {
+	ctx = logging.With(ctx, "consumer", consumer)
+	wake := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.waiters[wake] = struct{}{}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.waiters, wake)
+		b.mu.Unlock()
+	}()
+
+	offset, err := b.store.GetConsumerOffset(ctx, consumer)
+	if err != nil {
+		return err
+	}
+
+	for {
+		events, err := b.store.GetPublishedEvents(ctx, offset, memoryBatchSize)
+		failed := err != nil
+		if failed && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to read events", "error", err)
+		}
+
+		for _, e := range events {
+			if err := handler(ctx, e); err != nil {
+				// Смещение не сдвигаем: событие будет обработано повторно
+				slog.ErrorContext(ctx, "event handling failed", "event_id", e.ID, "event_type", e.Type, "error", err)
+				failed = true
+				break
+			}
+			if err := b.store.SaveConsumerOffset(ctx, consumer, e.Sequence); err != nil {
+				slog.ErrorContext(ctx, "failed to save consumer offset", "error", err)
+				failed = true
+				break
+			}
+			offset = e.Sequence
+		}
+
+		if failed {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+		if len(events) == memoryBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-time.After(memoryPollInterval):
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/logging"
+	"money-transfer-service/internal/models"
+)
+
+const (
+	// StreamName — поток Redis, в который relay публикует события
+	StreamName = "events:domain"
+
+	streamMaxLen    = 100000
+	streamBatchSize = 100
+	streamBlock     = 5 * time.Second
+	// Сообщения, которые потребитель не подтвердил за это время, забирает другой экземпляр
+	claimIdle = time.Minute
+)
+
+// RedisStreamBus публикует события в Redis Streams. Каждый потребитель — consumer group:
+// смещение группы и список неподтвержденных сообщений хранит Redis, поэтому события
+// между экземплярами сервиса распределяются, а упавший обработчик получает их повторно.
+type RedisStreamBus struct {
+	client *redis.Client
+	name   string
+}
+
+func NewRedisStreamBus(client *redis.Client) *RedisStreamBus {
+	host, _ := os.Hostname()
+	return &RedisStreamBus{client: client, name: fmt.Sprintf("%s-%d", host, os.Getpid())}
+}
+
+func (b *RedisStreamBus) Publish(ctx context.Context, event models.DomainEvent) error {
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: StreamName,
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"sequence":       event.Sequence,
+			"id":             event.ID.String(),
+			"type":           event.Type,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   event.AggregateID.String(),
+			"payload":        string(event.Payload),
+			"created_at":     event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		},
+	}).Err()
+}
+
+func (b *RedisStreamBus) Subscribe(ctx context.Context, consumer string, handler Handler) error {
+	ctx = logging.With(ctx, "consumer", consumer)
+	// Новая группа начинает с начала потока, чтобы не пропустить уже опубликованное
+	err := b.client.XGroupCreateMkStream(ctx, StreamName, consumer, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s: %w", consumer, err)
+	}
+
+	// Сначала дочитываем свои неподтвержденные сообщения, затем новые
+	pending := true
+	lastClaim := time.Time{}
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if time.Since(lastClaim) > claimIdle {
+			if b.claimStale(ctx, consumer) {
+				pending = true
+			}
+			lastClaim = time.Now()
+		}
+
+		start := ">"
+		if pending {
+			start = "0"
+		}
+		streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    consumer,
+			Consumer: b.name,
+			Streams:  []string{StreamName, start},
+			Count:    streamBatchSize,
+			Block:    streamBlock,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to read stream", "error", err)
+				sleep(ctx, retryDelay)
+			}
+			continue
+		}
+
+		var messages []redis.XMessage
+		for _, s := range streams {
+			messages = append(messages, s.Messages...)
+		}
+		if pending && len(messages) == 0 {
+			pending = false
+			continue
+		}
+
+		if !b.handle(ctx, consumer, messages, handler) {
+			// Неподтвержденные сообщения остались в PEL — перечитаем их после паузы
+			pending = true
+			sleep(ctx, retryDelay)
+		}
+	}
+}
+
+// handle обрабатывает сообщения по порядку и подтверждает успешные. false — на каком-то
+// сообщении обработчик вернул ошибку.
+func (b *RedisStreamBus) handle(ctx context.Context, consumer string, messages []redis.XMessage, handler Handler) bool {
+	for _, msg := range messages {
+		event, err := decodeStreamMessage(msg)
+		if err != nil {
+			// Испорченное сообщение повторять бессмысленно
+			slog.WarnContext(ctx, "skipping malformed message", "message_id", msg.ID, "error", err)
+		} else if err := handler(ctx, *event); err != nil {
+			slog.ErrorContext(ctx, "event handling failed", "event_id", event.ID, "event_type", event.Type, "error", err)
+			return false
+		}
+
+		if err := b.client.XAck(ctx, StreamName, consumer, msg.ID).Err(); err != nil {
+			slog.ErrorContext(ctx, "failed to ack message", "message_id", msg.ID, "error", err)
+			return false
+		}
+	}
+	return true
+}
+
+// claimStale забирает себе сообщения, зависшие у остановленных экземпляров.
+// Возвращает true, если что-то забрал.
+func (b *RedisStreamBus) claimStale(ctx context.Context, consumer string) bool {
+	claimed, _, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   StreamName,
+		Group:    consumer,
+		Consumer: b.name,
+		MinIdle:  claimIdle,
+		Start:    "0",
+		Count:    streamBatchSize,
+	}).Result()
+	if err != nil && err != redis.Nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "failed to claim stale messages", "error", err)
+	}
+	return len(claimed) > 0
+}
+
+func decodeStreamMessage(msg redis.XMessage) (*models.DomainEvent, error) {
+	field := func(name string) string {
+		v, _ := msg.Values[name].(string)
+		return v
+	}
+
+	var (
+		e   models.DomainEvent
+		err error
+	)
+	if e.Sequence, err = strconv.ParseInt(field("sequence"), 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid sequence: %w", err)
+	}
+	if e.ID, err = uuid.Parse(field("id")); err != nil {
+		return nil, fmt.Errorf("invalid id: %w", err)
+	}
+	if e.AggregateID, err = uuid.Parse(field("aggregate_id")); err != nil {
+		return nil, fmt.Errorf("invalid aggregate id: %w", err)
+	}
+	if e.CreatedAt, err = time.Parse(time.RFC3339Nano, field("created_at")); err != nil {
+		return nil, fmt.Errorf("invalid created_at: %w", err)
+	}
+	e.Type = field("type")
+	e.AggregateType = field("aggregate_type")
+
+	payload := field("payload")
+	if !json.Valid([]byte(payload)) {
+		return nil, fmt.Errorf("invalid payload")
+	}
+	e.Payload = json.RawMessage(payload)
+	return &e, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
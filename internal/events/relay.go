@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+)
+
+const relayBatchSize = 100
+
+// Relay переносит события из таблицы outbox в Publisher
+type Relay struct {
+	repo      *repository.Repository
+	publisher Publisher
+}
+
+func NewRelay(repo *repository.Repository, publisher Publisher) *Relay {
+	return &Relay{repo: repo, publisher: publisher}
+}
+
+// Run опрашивает outbox, пока не отменен контекст. Полная пачка означает,
+// что в очереди есть еще события, и следующая итерация начинается сразу.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := r.repo.ProcessOutbox(ctx, relayBatchSize, func(e models.DomainEvent) error {
+			return r.publisher.Publish(ctx, e)
+		})
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "outbox relay failed", "published", n, "error", err)
+		}
+		if c, ok := r.publisher.(committer); ok && n > 0 {
+			c.Committed()
+		}
+		if n == relayBatchSize && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/realtime"
+	"money-transfer-service/internal/service"
+	pb "money-transfer-service/pkg/pb/moneytransfer/v1"
+)
+
+type accountServer struct {
+	pb.UnimplementedAccountServiceServer
+	svc      *service.Service
+	shutdown <-chan struct{}
+}
+
+func (s *accountServer) balance(ctx context.Context) (*models.Balance, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.svc.GetAccountByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	balance, err := s.svc.GetBalance(ctx, account.ID)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return balance, nil
+}
+
+func (s *accountServer) GetBalance(ctx context.Context, _ *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	balance, err := s.balance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetBalanceResponse{Balance: balanceToPB(balance)}, nil
+}
+
+func (s *accountServer) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.svc.GetAccountByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if err := s.svc.DepositMoney(ctx, account.ID, req.GetAmount()); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	balance, err := s.svc.GetBalance(ctx, account.ID)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.DepositResponse{Balance: balanceToPB(balance)}, nil
+}
+
+// WatchBalance — то же, что SSE-поток в REST, но только изменения баланса
+func (s *accountServer) WatchBalance(_ *pb.WatchBalanceRequest, stream grpc.ServerStreamingServer[pb.WatchBalanceResponse]) error {
+	ctx := stream.Context()
+	user, err := currentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Подписываемся до чтения снимка, чтобы не пропустить изменения между ними
+	updates, unsubscribe := s.svc.SubscribeUpdates(user.ID)
+	defer unsubscribe()
+
+	balance, err := s.balance(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.WatchBalanceResponse{Balance: balanceToPB(balance)}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.shutdown:
+			// Сервер останавливается: клиент переподключится к другому экземпляру
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case msg, ok := <-updates:
+			if !ok {
+				return status.Error(codes.Unavailable, "updates closed")
+			}
+			if msg.Type != realtime.MessageBalance {
+				continue
+			}
+			var b models.Balance
+			if err := json.Unmarshal(msg.Data, &b); err != nil {
+				return toStatus(ctx, err)
+			}
+			if err := stream.Send(&pb.WatchBalanceResponse{Balance: balanceToPB(&b)}); err != nil {
+				return err
+			}
+		}
+	}
+}
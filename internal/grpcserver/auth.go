@@ -0,0 +1,88 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/service"
+	pb "money-transfer-service/pkg/pb/moneytransfer/v1"
+)
+
+type authServer struct {
+	pb.UnimplementedAuthServiceServer
+	svc *service.Service
+}
+
+func (s *authServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	in := models.RegisterRequest{Email: req.GetEmail(), Password: req.GetPassword(), FullName: req.GetFullName()}
+	if err := validate(in); err != nil {
+		return nil, err
+	}
+	resp, err := s.svc.Register(ctx, in, loginDevice(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.RegisterResponse{Token: resp.Token, User: userToPB(resp.User)}, nil
+}
+
+func (s *authServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	in := models.LoginRequest{Email: req.GetEmail(), Password: req.GetPassword()}
+	if err := validate(in); err != nil {
+		return nil, err
+	}
+	resp, err := s.svc.Login(ctx, in, loginDevice(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.LoginResponse{Token: resp.Token, User: userToPB(resp.User)}, nil
+}
+
+func (s *authServer) WhoAmI(ctx context.Context, _ *pb.WhoAmIRequest) (*pb.WhoAmIResponse, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.WhoAmIResponse{User: userToPB(*user)}, nil
+}
+
+// currentUser возвращает пользователя, которого положил authenticator
+func currentUser(ctx context.Context) (*models.User, error) {
+	user, ok := ctx.Value("user").(*models.User)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+	return user, nil
+}
+
+// loginDevice собирает устройство входа так же, как REST: x-device-id или user-agent и адрес клиента
+func loginDevice(ctx context.Context) service.LoginDevice {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var userAgent, deviceID string
+	if v := md.Get("user-agent"); len(v) > 0 {
+		userAgent = v[0]
+	}
+	if v := md.Get("x-device-id"); len(v) > 0 {
+		deviceID = v[0]
+	}
+	if deviceID == "" {
+		deviceID = userAgent
+	}
+	sum := sha256.Sum256([]byte(deviceID))
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	return service.LoginDevice{Fingerprint: hex.EncodeToString(sum[:]), UserAgent: userAgent, IP: ip}
+}
@@ -0,0 +1,157 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"money-transfer-service/internal/auth"
+	"money-transfer-service/internal/config"
+	"money-transfer-service/internal/logging"
+	"money-transfer-service/internal/repository"
+	pb "money-transfer-service/pkg/pb/moneytransfer/v1"
+)
+
+// Методы без аутентификации: вход, регистрация, проверки здоровья и отражение
+var publicMethods = map[string]bool{
+	pb.AuthService_Register_FullMethodName: true,
+	pb.AuthService_Login_FullMethodName:    true,
+}
+
+var publicPrefixes = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+func isPublic(method string) bool {
+	if publicMethods[method] {
+		return true
+	}
+	for _, prefix := range publicPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKey — ключ сервисного клиента. Храним хеш: сравнение по нему не зависит от длины ключа.
+type apiKey struct {
+	name   string
+	userID uuid.UUID
+	digest [sha256.Size]byte
+}
+
+// authenticator пускает по JWT пользователя (authorization: Bearer ...)
+// или по ключу сервиса (x-api-key), привязанному к сервисному пользователю
+type authenticator struct {
+	repo *repository.Repository
+	keys []apiKey
+}
+
+func newAuthenticator(repo *repository.Repository, keys config.APIKeys) (*authenticator, error) {
+	a := &authenticator{repo: repo}
+	for _, k := range keys {
+		userID, err := uuid.Parse(k.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("api key %q: invalid user_id: %w", k.Name, err)
+		}
+		a.keys = append(a.keys, apiKey{
+			name:   k.Name,
+			userID: userID,
+			digest: sha256.Sum256([]byte(k.Key.Value())),
+		})
+	}
+	return a, nil
+}
+
+func (a *authenticator) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isPublic(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isPublic(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate кладет пользователя в контекст под ключом "user", как AuthMiddleware в REST
+func (a *authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var (
+		userID uuid.UUID
+		client string
+	)
+	switch {
+	case len(md.Get("x-api-key")) > 0:
+		key, ok := a.lookupKey(md.Get("x-api-key")[0])
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+		userID, client = key.userID, key.name
+	case len(md.Get("authorization")) > 0:
+		token, ok := strings.CutPrefix(md.Get("authorization")[0], "Bearer ")
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "authorization must be: Bearer {token}")
+		}
+		id, err := auth.UserIDFromToken(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		userID = id
+	default:
+		return nil, status.Error(codes.Unauthenticated, "authorization or x-api-key required")
+	}
+
+	user, err := a.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load user", "user_id", userID, "error", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	if user == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not found")
+	}
+
+	logging.SetUserID(ctx, user.ID.String())
+	if client != "" {
+		ctx = logging.With(ctx, "client", client)
+	}
+	return context.WithValue(ctx, "user", user), nil
+}
+
+// lookupKey сравнивает ключ со всеми настроенными за постоянное время
+func (a *authenticator) lookupKey(raw string) (apiKey, bool) {
+	digest := sha256.Sum256([]byte(raw))
+	var (
+		found apiKey
+		ok    bool
+	)
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(digest[:], k.digest[:]) == 1 {
+			found, ok = k, true
+		}
+	}
+	return found, ok
+}
@@ -0,0 +1,50 @@
+package grpcserver
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/service"
+	pb "money-transfer-service/pkg/pb/moneytransfer/v1"
+)
+
+func userToPB(u models.User) *pb.User {
+	return &pb.User{
+		Id:        u.ID.String(),
+		Email:     u.Email,
+		FullName:  u.FullName,
+		Role:      u.Role,
+		CreatedAt: timestamppb.New(u.CreatedAt),
+	}
+}
+
+func balanceToPB(b *models.Balance) *pb.Balance {
+	return &pb.Balance{
+		AccountId:        b.AccountID.String(),
+		AccountNumber:    b.AccountNumber,
+		Balance:          b.Ledger,
+		AvailableBalance: b.Available,
+		HeldBalance:      b.Held,
+		Currency:         b.Currency,
+	}
+}
+
+func transferToPB(t models.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:            t.ID.String(),
+		FromAccountId: t.From.String(),
+		ToAccountId:   t.To.String(),
+		FromEmail:     t.FromEmail,
+		ToEmail:       t.ToEmail,
+		Amount:        t.Amount,
+		Currency:      t.Currency,
+		Memo:          t.Memo,
+		Reference:     t.Reference,
+		Tags:          t.Tags,
+		CreatedAt:     timestamppb.New(t.CreatedAt),
+	}
+}
+
+func rateToPB(currency string, rate float64) *pb.Rate {
+	return &pb.Rate{Currency: currency, Rate: rate, Base: service.BaseCurrency}
+}
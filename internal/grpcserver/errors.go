@@ -0,0 +1,99 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"money-transfer-service/internal/accountnumber"
+	"money-transfer-service/internal/alias"
+	"money-transfer-service/internal/repository"
+	"money-transfer-service/internal/service"
+	"money-transfer-service/internal/validation"
+)
+
+// errorDomain — домен в ErrorInfo; reason в его пределах совпадает с кодом ошибки REST API
+const errorDomain = "money-transfer"
+
+// domainError связывает доменную ошибку с кодом gRPC и стабильной причиной для клиентов.
+// Причины — часть API: их нельзя переименовывать, только добавлять новые.
+type domainError struct {
+	err    error
+	code   codes.Code
+	reason string
+}
+
+var domainErrors = []domainError{
+	{repository.ErrInsufficientFunds, codes.FailedPrecondition, "INSUFFICIENT_FUNDS"},
+	{repository.ErrAccountNotFound, codes.NotFound, "ACCOUNT_NOT_FOUND"},
+	{repository.ErrAccountFrozen, codes.FailedPrecondition, "ACCOUNT_FROZEN"},
+	{repository.ErrAccountClosed, codes.FailedPrecondition, "ACCOUNT_CLOSED"},
+	{repository.ErrDebitBlocked, codes.FailedPrecondition, "DEBIT_BLOCKED"},
+	{repository.ErrCreditBlocked, codes.FailedPrecondition, "CREDIT_BLOCKED"},
+	{repository.ErrLimitExceeded, codes.ResourceExhausted, "LIMIT_EXCEEDED"},
+	{service.ErrInvalidAmount, codes.InvalidArgument, "INVALID_AMOUNT"},
+	{service.ErrUnsupportedCurrency, codes.InvalidArgument, "UNSUPPORTED_CURRENCY"},
+
+	{service.ErrStepUpRequired, codes.PermissionDenied, "STEP_UP_REQUIRED"},
+	{service.ErrStepUpFailed, codes.PermissionDenied, "INVALID_PASSWORD"},
+	{service.ErrBeneficiaryMissing, codes.NotFound, "BENEFICIARY_NOT_FOUND"},
+
+	{alias.ErrUnknownType, codes.InvalidArgument, "UNKNOWN_ALIAS_TYPE"},
+	{alias.ErrInvalid, codes.InvalidArgument, "INVALID_ALIAS"},
+	{accountnumber.ErrInvalid, codes.InvalidArgument, "INVALID_ACCOUNT_NUMBER"},
+	{service.ErrAliasNotFound, codes.NotFound, "ALIAS_NOT_FOUND"},
+	{repository.ErrAliasCodeLimited, codes.ResourceExhausted, "VERIFICATION_CODE_LIMITED"},
+	{repository.ErrAliasLocked, codes.ResourceExhausted, "ALIAS_LOCKED"},
+
+	{service.ErrUserExists, codes.AlreadyExists, "USER_EXISTS"},
+	{service.ErrInvalidCredentials, codes.Unauthenticated, "INVALID_CREDENTIALS"},
+}
+
+// toStatus переводит ошибку сервиса в статус gRPC. Текст внутренних ошибок клиенту не отдается.
+func toStatus(ctx context.Context, err error) error {
+	for _, d := range domainErrors {
+		if errors.Is(err, d.err) {
+			return withDetails(status.New(d.code, err.Error()), &errdetails.ErrorInfo{
+				Reason: d.reason,
+				Domain: errorDomain,
+			})
+		}
+	}
+	slog.ErrorContext(ctx, "rpc failed", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// validate проверяет DTO по тегам validate, как REST-обработчики
+func validate(v interface{}) error {
+	fields := validation.Struct(v)
+	if len(fields) == 0 {
+		return nil
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	messages := make([]string, 0, len(fields))
+	for _, f := range fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+		messages = append(messages, f.Field+": "+f.Message)
+	}
+	return withDetails(status.New(codes.InvalidArgument, strings.Join(messages, "; ")),
+		&errdetails.ErrorInfo{Reason: "VALIDATION_FAILED", Domain: errorDomain},
+		&errdetails.BadRequest{FieldViolations: violations},
+	)
+}
+
+// withDetails прикладывает к статусу детали; при ошибке сериализации отдает статус без них
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	if detailed, err := st.WithDetails(details...); err == nil {
+		return detailed.Err()
+	}
+	return st.Err()
+}
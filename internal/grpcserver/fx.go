@@ -0,0 +1,65 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"money-transfer-service/internal/service"
+	"money-transfer-service/internal/validation"
+	pb "money-transfer-service/pkg/pb/moneytransfer/v1"
+)
+
+type fxServer struct {
+	pb.UnimplementedFXServiceServer
+	svc *service.Service
+}
+
+// checkCurrency приводит код к верхнему регистру и отсекает валюты без курса
+func checkCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !validation.IsSupportedCurrency(code) {
+		return "", service.ErrUnsupportedCurrency
+	}
+	return code, nil
+}
+
+func (s *fxServer) ListRates(ctx context.Context, _ *pb.ListRatesRequest) (*pb.ListRatesResponse, error) {
+	currencies := s.svc.Currencies()
+	rates := make([]*pb.Rate, 0, len(currencies))
+	for _, currency := range currencies {
+		rate, err := s.svc.ExchangeRate(ctx, currency)
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+		rates = append(rates, rateToPB(currency, rate))
+	}
+	return &pb.ListRatesResponse{Rates: rates}, nil
+}
+
+func (s *fxServer) GetRate(ctx context.Context, req *pb.GetRateRequest) (*pb.GetRateResponse, error) {
+	currency, err := checkCurrency(req.GetCurrency())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	rate, err := s.svc.ExchangeRate(ctx, currency)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.GetRateResponse{Rate: rateToPB(currency, rate)}, nil
+}
+
+func (s *fxServer) Convert(ctx context.Context, req *pb.ConvertRequest) (*pb.ConvertResponse, error) {
+	from, err := checkCurrency(req.GetFrom())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	to, err := checkCurrency(req.GetTo())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	amount, rate, err := s.svc.Convert(ctx, req.GetAmount(), from, to)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.ConvertResponse{Amount: amount, Currency: to, Rate: rate}, nil
+}
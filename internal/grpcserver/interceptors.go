@@ -0,0 +1,106 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"money-transfer-service/internal/logging"
+	"money-transfer-service/internal/metrics"
+)
+
+// requestIDKey — метаданные с идентификатором запроса, как X-Request-ID в HTTP
+const requestIDKey = "x-request-id"
+
+// wrappedStream подменяет контекст потока
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
+
+// startRequest берет x-request-id из метаданных или создает новый и возвращает его клиенту
+func startRequest(ctx context.Context) context.Context {
+	var incoming string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDKey); len(values) > 0 {
+			incoming = values[0]
+		}
+	}
+	ctx, id := logging.WithRequestID(ctx, incoming)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("rpc.request_id", id))
+	return ctx
+}
+
+// logUnary пишет по строке на вызов и превращает панику в codes.Internal вместо падения процесса
+func logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	ctx = startRequest(ctx)
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(ctx, p)
+		}
+		logCall(ctx, info.FullMethod, err, start)
+	}()
+	return handler(ctx, req)
+}
+
+func logStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx := startRequest(ss.Context())
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(ctx, p)
+		}
+		logCall(ctx, info.FullMethod, err, start)
+	}()
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+}
+
+func recovered(ctx context.Context, p interface{}) error {
+	slog.ErrorContext(ctx, "panic in rpc handler", "panic", p, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
+
+func logCall(ctx context.Context, method string, err error, start time.Time) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	}
+	slog.LogAttrs(ctx, level, "rpc",
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+func metricsUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return resp, err
+}
+
+func metricsStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	done := metrics.TrackGRPCStream(info.FullMethod)
+	defer done()
+
+	err := handler(srv, ss)
+	metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return err
+}
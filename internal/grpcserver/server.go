@@ -0,0 +1,79 @@
+// Package grpcserver — gRPC API для внутренних сервисов. Работает рядом с REST и вызывает
+// те же методы service.Service; аутентификация, логи и метрики — в интерсепторах.
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"money-transfer-service/internal/config"
+	"money-transfer-service/internal/repository"
+	"money-transfer-service/internal/service"
+	pb "money-transfer-service/pkg/pb/moneytransfer/v1"
+)
+
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+
+	// shutdown закрывается при остановке, чтобы бесконечные потоки завершились
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+func New(svc *service.Service, repo *repository.Repository, keys config.APIKeys) (*Server, error) {
+	authn, err := newAuthenticator(repo, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		health:   health.NewServer(),
+		shutdown: make(chan struct{}),
+	}
+	// Порядок важен: логи видят request_id, а метрики — код ответа после проверки доступа
+	s.grpc = grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(logUnary, metricsUnary, authn.unary),
+		grpc.ChainStreamInterceptor(logStream, metricsStream, authn.stream),
+	)
+
+	pb.RegisterAuthServiceServer(s.grpc, &authServer{svc: svc})
+	pb.RegisterAccountServiceServer(s.grpc, &accountServer{svc: svc, shutdown: s.shutdown})
+	pb.RegisterTransferServiceServer(s.grpc, &transferServer{svc: svc})
+	pb.RegisterFXServiceServer(s.grpc, &fxServer{svc: svc})
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	// Отражение нужно grpcurl и подобным инструментам
+	reflection.Register(s.grpc)
+
+	return s, nil
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown дожидается текущих вызовов, пока не истечет ctx, затем обрывает оставшиеся.
+// Открытые потоки обновлений закрываются сразу: клиенты переподключатся к другому экземпляру.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.grpc.Stop()
+	}
+}
@@ -0,0 +1,94 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/service"
+	pb "money-transfer-service/pkg/pb/moneytransfer/v1"
+)
+
+type transferServer struct {
+	pb.UnimplementedTransferServiceServer
+	svc *service.Service
+}
+
+// transferInput — поля CreateTransferRequest с теми же правилами, что и в REST
+type transferInput struct {
+	Amount   float64 `json:"amount" validate:"gt=0"`
+	Currency string  `json:"currency" validate:"required,currency"`
+	models.TransferDetails
+}
+
+func (s *transferServer) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	in := transferInput{
+		Amount:          req.GetAmount(),
+		Currency:        req.GetCurrency(),
+		TransferDetails: models.TransferDetails{Memo: req.GetMemo(), Reference: req.GetReference()},
+	}
+	if err := validate(in); err != nil {
+		return nil, err
+	}
+
+	var transferID uuid.UUID
+	switch recipient := req.GetRecipient().(type) {
+	case *pb.CreateTransferRequest_BeneficiaryId:
+		beneficiaryID, perr := uuid.Parse(recipient.BeneficiaryId)
+		if perr != nil {
+			return nil, status.Error(codes.InvalidArgument, "beneficiary_id must be a UUID")
+		}
+		transferID, err = s.svc.TransferToBeneficiary(ctx, user.ID, beneficiaryID, in.Amount, in.Currency, in.TransferDetails, req.GetPassword())
+	case *pb.CreateTransferRequest_To:
+		to := models.Recipient{Type: recipient.To.GetType(), Value: recipient.To.GetValue()}
+		if err := validate(to); err != nil {
+			return nil, err
+		}
+		transferID, err = s.svc.TransferToRecipient(ctx, user.ID, to, in.Amount, in.Currency, in.TransferDetails, req.GetPassword())
+	default:
+		return nil, status.Error(codes.InvalidArgument, "recipient or beneficiary is required")
+	}
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.CreateTransferResponse{TransferId: transferID.String()}, nil
+}
+
+// ListTransfers отдает историю по одному переводу в сообщении, чтобы клиент не ждал весь список
+func (s *transferServer) ListTransfers(req *pb.ListTransfersRequest, stream grpc.ServerStreamingServer[pb.ListTransfersResponse]) error {
+	ctx := stream.Context()
+	user, err := currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	account, err := s.svc.GetAccountByUserID(ctx, user.ID)
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+
+	var transfers []models.Transfer
+	if query := strings.TrimSpace(req.GetQuery()); query != "" {
+		transfers, err = s.svc.SearchTransfers(ctx, account.ID, query)
+	} else {
+		transfers, err = s.svc.GetTransfersHistory(ctx, account.ID)
+	}
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+
+	for _, t := range transfers {
+		if err := stream.Send(&pb.ListTransfersResponse{Transfer: transferToPB(t)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
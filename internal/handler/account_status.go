@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+)
+
+func writeAccountStatusError(w http.ResponseWriter, err error) {
+	writeError(w, err, http.StatusConflict)
+}
+
+func (h *Handler) FreezeAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.FreezeAccountRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	account, err := h.service.FreezeOwnAccount(r.Context(), user.ID, req.Reason)
+	if err != nil {
+		writeAccountStatusError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+func (h *Handler) UnfreezeAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.FreezeAccountRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	account, err := h.service.UnfreezeOwnAccount(r.Context(), user.ID, req.Reason)
+	if err != nil {
+		writeAccountStatusError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+func (h *Handler) CloseAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CloseAccountRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	account, err := h.service.CloseOwnAccount(r.Context(), user.ID, req)
+	if err != nil {
+		writeAccountStatusError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+func (h *Handler) GetAccountStatusHistory(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	history, err := h.service.GetOwnAccountStatusHistory(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+func (h *Handler) AdminGetAccount(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid account ID", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.service.GetAccount(r.Context(), accountID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if account == nil {
+		problem.Error(w, "Account not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+func (h *Handler) AdminSetAccountStatus(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid account ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetAccountStatusRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	account, err := h.service.SetAccountStatus(r.Context(), user.ID, accountID, req)
+	if err != nil {
+		writeAccountStatusError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+func (h *Handler) AdminGetAccountStatusHistory(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid account ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.GetAccountStatusHistory(r.Context(), accountID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+)
+
+func (h *Handler) GetAliases(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	aliases, err := h.service.GetAliases(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aliases)
+}
+
+func (h *Handler) CreateAlias(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateAliasRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	a, err := h.service.CreateAlias(r.Context(), user.ID, req)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	// Телефон ждет подтверждения кодом
+	status := http.StatusCreated
+	if !a.Verified {
+		status = http.StatusAccepted
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(a)
+}
+
+func (h *Handler) VerifyAlias(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid alias ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.VerifyAliasRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	a, err := h.service.VerifyAlias(r.Context(), user.ID, id, req.Code)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a)
+}
+
+func (h *Handler) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid alias ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteAlias(r.Context(), user.ID, id); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
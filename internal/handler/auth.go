@@ -1,113 +1,81 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 
-	"money-transfer-service/internal/auth"
 	"money-transfer-service/internal/models"
-	"money-transfer-service/internal/repository"
+	"money-transfer-service/internal/problem"
+	"money-transfer-service/internal/service"
 )
 
 type AuthHandler struct {
-	repo *repository.Repository
+	service *service.Service
 }
 
-func NewAuthHandler(repo *repository.Repository) *AuthHandler {
-	return &AuthHandler{repo: repo}
+func NewAuthHandler(service *service.Service) *AuthHandler {
+	return &AuthHandler{service: service}
 }
 
-func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	var req models.RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Проверяем, существует ли пользователь
-	existingUser, err := h.repo.GetUserByEmail(r.Context(), req.Email)
-	if err != nil {
-		http.Error(w, "Error checking user existence", http.StatusInternalServerError)
-		return
-	}
-	if existingUser != nil {
-		http.Error(w, "User already exists", http.StatusConflict)
-		return
+// deviceFingerprint определяет устройство по заголовку X-Device-ID, который клиент может
+// передавать, или, если его нет, по User-Agent
+func deviceFingerprint(r *http.Request) string {
+	id := r.Header.Get("X-Device-ID")
+	if id == "" {
+		id = r.UserAgent()
 	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
 
-	// Хэшируем пароль
-	hashedPassword, err := auth.HashPassword(req.Password)
+func loginDevice(r *http.Request) service.LoginDevice {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
-		http.Error(w, "Error creating user", http.StatusInternalServerError)
-		return
+		ip = r.RemoteAddr
 	}
+	return service.LoginDevice{Fingerprint: deviceFingerprint(r), UserAgent: r.UserAgent(), IP: ip}
+}
 
-	// Создаем пользователя
-	user, err := h.repo.CreateUser(r.Context(), req.Email, hashedPassword, req.FullName)
-	if err != nil {
-		http.Error(w, "Error creating user", http.StatusInternalServerError)
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var req models.RegisterRequest
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	// Создаем счет для пользователя
-	_, err = h.repo.CreateAccount(r.Context(), user.ID)
-	if err != nil {
-		http.Error(w, "Error creating account", http.StatusInternalServerError)
+	response, err := h.service.Register(r.Context(), req, loginDevice(r))
+	if errors.Is(err, service.ErrUserExists) {
+		problem.Error(w, "User already exists", http.StatusConflict)
 		return
 	}
-
-	// Генерируем JWT токен
-	token, err := auth.GenerateJWT(*user)
 	if err != nil {
-		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		writeError(w, err, http.StatusInternalServerError)
 		return
 	}
 
-	response := models.AuthResponse{
-		Token: token,
-		User:  *user,
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Ищем пользователя по email
-	user, err := h.repo.GetUserByEmail(r.Context(), req.Email)
-	if err != nil {
-		http.Error(w, "Error finding user", http.StatusInternalServerError)
-		return
-	}
-	if user == nil {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	// Проверяем пароль
-	if !auth.CheckPasswordHash(req.Password, user.PasswordHash) {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+	response, err := h.service.Login(r.Context(), req, loginDevice(r))
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		problem.Error(w, "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
-
-	// Генерируем JWT токен
-	token, err := auth.GenerateJWT(*user)
 	if err != nil {
-		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		writeError(w, err, http.StatusInternalServerError)
 		return
 	}
 
-	response := models.AuthResponse{
-		Token: token,
-		User:  *user,
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
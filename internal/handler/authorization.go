@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+)
+
+func writeAuthorizationError(w http.ResponseWriter, err error) {
+	writeError(w, err, http.StatusBadRequest)
+}
+
+func (h *Handler) Authorize(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.AuthorizeRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	z, err := h.service.Authorize(r.Context(), user.ID, req)
+	if err != nil {
+		writeAuthorizationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(z)
+}
+
+func (h *Handler) ListAuthorizations(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	authorizations, err := h.service.GetAuthorizations(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(authorizations)
+}
+
+func (h *Handler) GetAuthorization(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid authorization ID", http.StatusBadRequest)
+		return
+	}
+
+	z, err := h.service.GetAuthorization(r.Context(), user.ID, id)
+	if err != nil {
+		writeAuthorizationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(z)
+}
+
+func (h *Handler) CaptureAuthorization(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid authorization ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CaptureRequest
+	if r.ContentLength != 0 {
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+	}
+
+	z, err := h.service.Capture(r.Context(), user.ID, id, req.Amount)
+	if err != nil {
+		writeAuthorizationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(z)
+}
+
+func (h *Handler) VoidAuthorization(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid authorization ID", http.StatusBadRequest)
+		return
+	}
+
+	z, err := h.service.Void(r.Context(), user.ID, id)
+	if err != nil {
+		writeAuthorizationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(z)
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+	"money-transfer-service/internal/service"
+)
+
+const maxBatchBodySize = 1 << 20
+
+// CreateTransferBatch принимает пакет переводов в JSON или CSV.
+// Для CSV режим передается в query-параметре mode, пароль — в заголовке X-Confirm-Password.
+func (h *Handler) CreateTransferBatch(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodySize)
+
+	var req models.BatchTransferRequest
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		items, err := parseBatchCSV(r.Body)
+		if err != nil {
+			writeError(w, err, http.StatusBadRequest)
+			return
+		}
+		req.Items = items
+		req.Password = r.Header.Get("X-Confirm-Password")
+	} else if !decodeJSONLimit(w, r, &req, maxBatchBodySize) {
+		return
+	}
+
+	if mode := r.URL.Query().Get("mode"); mode != "" {
+		req.Mode = mode
+	}
+
+	batch, lineErrors, err := h.service.SubmitTransferBatch(r.Context(), user.ID, req)
+	if errors.Is(err, service.ErrBatchInvalid) {
+		p := problemFor(err, http.StatusUnprocessableEntity)
+		p.Errors = lineErrors
+		problem.Write(w, p)
+		return
+	}
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/transfers/batch/"+batch.ID.String())
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(batch)
+}
+
+func (h *Handler) GetTransferBatch(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid batch ID", http.StatusBadRequest)
+		return
+	}
+
+	batch, err := h.service.GetTransferBatch(r.Context(), user.ID, id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if batch == nil {
+		problem.Error(w, "Batch not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(batch)
+}
+
+// parseBatchCSV ожидает строку заголовка с колонками to_email и amount;
+// колонки currency, memo и reference необязательны
+func parseBatchCSV(body io.Reader) ([]models.BatchTransferLine, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"to_email", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header must contain column %q", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var lines []models.BatchTransferLine
+	for lineNo := 1; ; lineNo++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		amount, err := strconv.ParseFloat(field(record, "amount"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount", lineNo)
+		}
+
+		lines = append(lines, models.BatchTransferLine{
+			ToEmail:  field(record, "to_email"),
+			Amount:   amount,
+			Currency: field(record, "currency"),
+			TransferDetails: models.TransferDetails{
+				Memo:      field(record, "memo"),
+				Reference: field(record, "reference"),
+			},
+		})
+
+		if len(lines) > service.MaxBatchLines {
+			return nil, fmt.Errorf("batch must contain at most %d lines", service.MaxBatchLines)
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+	"money-transfer-service/internal/validation"
+)
+
+func (h *Handler) ListBeneficiaries(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	beneficiaries, err := h.service.GetBeneficiaries(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(beneficiaries)
+}
+
+func (h *Handler) CreateBeneficiary(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateBeneficiaryRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	b, err := h.service.CreateBeneficiary(r.Context(), user.ID, req)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(b)
+}
+
+func (h *Handler) UpdateBeneficiary(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid beneficiary ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateBeneficiaryRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	b, err := h.service.UpdateBeneficiary(r.Context(), user.ID, id, req)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(b)
+}
+
+func (h *Handler) DeleteBeneficiary(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid beneficiary ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.DeleteBeneficiary(r.Context(), user.ID, id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ConfirmRecipient показывает замаскированное имя получателя перед отправкой
+func (h *Handler) ConfirmRecipient(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// ?email= оставлен для совместимости со старыми клиентами
+	recipient := models.Recipient{
+		Type:  r.URL.Query().Get("type"),
+		Value: r.URL.Query().Get("value"),
+	}
+	if email := r.URL.Query().Get("email"); recipient.Type == "" && email != "" {
+		recipient = models.Recipient{Type: models.AliasEmail, Value: email}
+	}
+	if recipient.Type == "" || recipient.Value == "" {
+		problem.Error(w, "Recipient type and value are required", http.StatusBadRequest)
+		return
+	}
+
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		currency = "RUB"
+	}
+	if !validation.IsSupportedCurrency(currency) {
+		writeValidationError(w, []validation.FieldError{{Field: "currency", Code: "currency", Message: "is not a supported currency"}})
+		return
+	}
+
+	var amount float64
+	if v := r.URL.Query().Get("amount"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			problem.Error(w, "Invalid amount", http.StatusBadRequest)
+			return
+		}
+		amount = parsed
+	}
+
+	confirmation, err := h.service.ConfirmRecipient(r.Context(), user.ID, recipient, amount, currency)
+	if err != nil {
+		writeError(w, err, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(confirmation)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"money-transfer-service/internal/problem"
+	"money-transfer-service/internal/validation"
+)
+
+// maxBodySize — предел тела обычного JSON-запроса. Пакеты переводов принимают больше.
+const maxBodySize = 64 << 10
+
+// decodeJSON читает тело запроса в dst и проверяет его по тегам validate.
+// При ошибке отвечает problem+json и возвращает false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return decodeJSONLimit(w, r, dst, maxBodySize)
+}
+
+func decodeJSONLimit(w http.ResponseWriter, r *http.Request, dst interface{}, limit int64) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		writeDecodeError(w, err)
+		return false
+	}
+	// Тело должно содержать ровно один JSON-объект
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		writeDecodeError(w, errors.New("request body must contain a single JSON object"))
+		return false
+	}
+
+	return validateRequest(w, dst)
+}
+
+// validateRequest проверяет уже заполненный DTO (например, из query-параметров)
+func validateRequest(w http.ResponseWriter, req interface{}) bool {
+	if fields := validation.Struct(req); len(fields) > 0 {
+		writeValidationError(w, fields)
+		return false
+	}
+	return true
+}
+
+func writeValidationError(w http.ResponseWriter, fields []validation.FieldError) {
+	p := problem.New(http.StatusUnprocessableEntity, "validation_failed", "request validation failed")
+	p.Errors = fields
+	problem.Write(w, p)
+}
+
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var (
+		syntaxErr  *json.SyntaxError
+		typeErr    *json.UnmarshalTypeError
+		tooLarge   *http.MaxBytesError
+		unknownKey = "json: unknown field "
+	)
+
+	switch {
+	case errors.As(err, &tooLarge):
+		problem.Write(w, problem.New(http.StatusRequestEntityTooLarge, "body_too_large",
+			fmt.Sprintf("request body must not exceed %d bytes", tooLarge.Limit)))
+	case errors.As(err, &typeErr):
+		writeValidationError(w, []validation.FieldError{{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Param:   typeErr.Type.String(),
+			Message: "must be of type " + jsonType(typeErr.Type.Kind().String()),
+		}})
+	case strings.HasPrefix(err.Error(), unknownKey):
+		// encoding/json не экспортирует тип этой ошибки
+		field := strings.Trim(strings.TrimPrefix(err.Error(), unknownKey), `"`)
+		writeValidationError(w, []validation.FieldError{{
+			Field:   field,
+			Code:    "unknown_field",
+			Message: "is not a known field",
+		}})
+	case errors.Is(err, io.EOF):
+		problem.Write(w, problem.New(http.StatusBadRequest, "invalid_json", "request body is empty"))
+	case errors.As(err, &syntaxErr):
+		problem.Write(w, problem.New(http.StatusBadRequest, "invalid_json",
+			fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)))
+	default:
+		problem.Write(w, problem.New(http.StatusBadRequest, "invalid_json", err.Error()))
+	}
+}
+
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "number"
+	case kind == "bool":
+		return "boolean"
+	case kind == "slice", kind == "array":
+		return "array"
+	case kind == "struct", kind == "map":
+		return "object"
+	}
+	return kind
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"money-transfer-service/internal/accountnumber"
+	"money-transfer-service/internal/alias"
+	"money-transfer-service/internal/problem"
+	"money-transfer-service/internal/repository"
+	"money-transfer-service/internal/service"
+)
+
+// domainError связывает доменную ошибку с HTTP-статусом и стабильным кодом для фронтенда.
+// Коды — часть API: их нельзя переименовывать, только добавлять новые.
+type domainError struct {
+	err    error
+	status int
+	code   string
+}
+
+var domainErrors = []domainError{
+	{repository.ErrInsufficientFunds, http.StatusPaymentRequired, "insufficient_funds"},
+	{repository.ErrAccountNotFound, http.StatusNotFound, "account_not_found"},
+	{repository.ErrAccountFrozen, http.StatusConflict, "account_frozen"},
+	{repository.ErrAccountClosed, http.StatusConflict, "account_closed"},
+	{repository.ErrDebitBlocked, http.StatusConflict, "debit_blocked"},
+	{repository.ErrCreditBlocked, http.StatusConflict, "credit_blocked"},
+	{repository.ErrLimitExceeded, http.StatusUnprocessableEntity, "limit_exceeded"},
+	{service.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{service.ErrUnsupportedCurrency, http.StatusUnprocessableEntity, "unsupported_currency"},
+
+	{service.ErrStepUpRequired, http.StatusForbidden, "step_up_required"},
+	{service.ErrStepUpFailed, http.StatusForbidden, "invalid_password"},
+	{service.ErrBeneficiaryMissing, http.StatusNotFound, "beneficiary_not_found"},
+
+	{alias.ErrUnknownType, http.StatusBadRequest, "unknown_alias_type"},
+	{alias.ErrInvalid, http.StatusBadRequest, "invalid_alias"},
+	{accountnumber.ErrInvalid, http.StatusBadRequest, "invalid_account_number"},
+	{service.ErrAliasNotFound, http.StatusNotFound, "alias_not_found"},
+	{service.ErrPhoneVerificationUnavailable, http.StatusServiceUnavailable, "phone_verification_unavailable"},
+	{repository.ErrAliasTaken, http.StatusConflict, "alias_taken"},
+	{repository.ErrAliasCodeLimited, http.StatusTooManyRequests, "verification_code_limited"},
+	{repository.ErrAliasLocked, http.StatusTooManyRequests, "alias_locked"},
+
+	{service.ErrBatchInvalid, http.StatusUnprocessableEntity, "batch_invalid"},
+	{service.ErrGroupNotFound, http.StatusNotFound, "group_not_found"},
+	{service.ErrEscrowNotFound, http.StatusNotFound, "escrow_not_found"},
+	{repository.ErrEscrowState, http.StatusConflict, "escrow_state"},
+	{service.ErrAuthorizationNotFound, http.StatusNotFound, "authorization_not_found"},
+	{service.ErrPaymentLinkNotFound, http.StatusNotFound, "payment_link_not_found"},
+	{repository.ErrPaymentLinkInactive, http.StatusGone, "payment_link_inactive"},
+	{service.ErrMerchantNotFound, http.StatusNotFound, "merchant_not_found"},
+	{service.ErrCheckoutSessionNotFound, http.StatusNotFound, "checkout_session_not_found"},
+	{repository.ErrCheckoutSessionState, http.StatusConflict, "checkout_session_state"},
+	{service.ErrWebhookEndpointNotFound, http.StatusNotFound, "webhook_endpoint_not_found"},
+	{service.ErrWebhookDeliveryNotFound, http.StatusNotFound, "webhook_delivery_not_found"},
+}
+
+// problemFor подбирает статус и код для ошибки. Доменные ошибки получают свои,
+// остальные — fallback с общим кодом. Текст внутренних ошибок клиенту не отдается.
+func problemFor(err error, fallback int) problem.Problem {
+	for _, d := range domainErrors {
+		if errors.Is(err, d.err) {
+			return problem.New(d.status, d.code, err.Error())
+		}
+	}
+	if fallback >= http.StatusInternalServerError {
+		return problem.New(fallback, problem.CodeForStatus(fallback), http.StatusText(fallback))
+	}
+	return problem.New(fallback, problem.CodeForStatus(fallback), err.Error())
+}
+
+func writeError(w http.ResponseWriter, err error, fallback int) {
+	p := problemFor(err, fallback)
+	if p.Status >= http.StatusInternalServerError {
+		slog.Error("request failed", "error", err, "request_id", w.Header().Get("X-Request-ID"))
+	}
+	problem.Write(w, p)
+}
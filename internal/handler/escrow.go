@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+)
+
+func writeEscrowError(w http.ResponseWriter, err error) {
+	writeError(w, err, http.StatusBadRequest)
+}
+
+func (h *Handler) CreateEscrow(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateEscrowRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	e, err := h.service.CreateEscrow(r.Context(), user.ID, req)
+	if err != nil {
+		writeEscrowError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(e)
+}
+
+func (h *Handler) ListEscrows(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	escrows, err := h.service.GetEscrows(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(escrows)
+}
+
+func (h *Handler) GetEscrow(w http.ResponseWriter, r *http.Request) {
+	h.escrowAction(w, r, func(ctx context.Context, userID, id uuid.UUID) (*models.Escrow, error) {
+		e, _, err := h.service.GetEscrow(ctx, userID, id)
+		return e, err
+	})
+}
+
+func (h *Handler) ConfirmEscrow(w http.ResponseWriter, r *http.Request) {
+	h.escrowAction(w, r, h.service.ConfirmEscrow)
+}
+
+func (h *Handler) CancelEscrow(w http.ResponseWriter, r *http.Request) {
+	h.escrowAction(w, r, h.service.CancelEscrow)
+}
+
+func (h *Handler) DisputeEscrow(w http.ResponseWriter, r *http.Request) {
+	h.escrowAction(w, r, h.service.DisputeEscrow)
+}
+
+func (h *Handler) AdminResolveEscrow(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid escrow ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ResolveEscrowRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	e, err := h.service.ResolveEscrowDispute(r.Context(), user.ID, id, req)
+	if err != nil {
+		writeEscrowError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(e)
+}
+
+func (h *Handler) escrowAction(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, userID, id uuid.UUID) (*models.Escrow, error)) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid escrow ID", http.StatusBadRequest)
+		return
+	}
+
+	e, err := action(r.Context(), user.ID, id)
+	if err != nil {
+		writeEscrowError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(e)
+}
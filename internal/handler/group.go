@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+)
+
+func writeGroupError(w http.ResponseWriter, err error) {
+	writeError(w, err, http.StatusBadRequest)
+}
+
+func (h *Handler) CreateExpenseGroup(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateGroupRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	group, err := h.service.CreateExpenseGroup(r.Context(), user.ID, req)
+	if err != nil {
+		writeGroupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(group)
+}
+
+func (h *Handler) ListExpenseGroups(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groups, err := h.service.GetExpenseGroups(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+func (h *Handler) GetExpenseGroup(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.GetExpenseGroup(r.Context(), user.ID, groupID)
+	if err != nil {
+		writeGroupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+func (h *Handler) AddGroupMember(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.AddGroupMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	group, err := h.service.AddGroupMember(r.Context(), user.ID, groupID, req.Email)
+	if err != nil {
+		writeGroupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+func (h *Handler) AddExpense(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateExpenseRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	expense, err := h.service.AddExpense(r.Context(), user.ID, groupID, req)
+	if err != nil {
+		writeGroupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(expense)
+}
+
+func (h *Handler) ListGroupExpenses(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	expenses, err := h.service.GetGroupExpenses(r.Context(), user.ID, groupID)
+	if err != nil {
+		writeGroupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(expenses)
+}
+
+func (h *Handler) GetGroupBalances(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	balances, err := h.service.GetGroupBalances(r.Context(), user.ID, groupID)
+	if err != nil {
+		writeGroupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balances)
+}
+
+func (h *Handler) SettleUp(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SettleGroupRequest
+	if r.ContentLength != 0 {
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+	}
+
+	settlements, err := h.service.SettleUp(r.Context(), user.ID, groupID, req.Password)
+	if err != nil {
+		slog.WarnContext(r.Context(), "settle up failed", "group_id", groupID, "error", err)
+		writeGroupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"settlements": settlements,
+	})
+}
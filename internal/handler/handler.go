@@ -2,100 +2,135 @@ package handler
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
+	"sync"
 
-	_ "github.com/google/uuid"
+	"github.com/google/uuid"
 
 	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
 	"money-transfer-service/internal/service"
 )
 
 type Handler struct {
-	service *service.Service
+	service       *service.Service
+	publicBaseURL string
+
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+func NewHandler(service *service.Service, publicBaseURL string) *Handler {
+	return &Handler{service: service, publicBaseURL: publicBaseURL, shutdown: make(chan struct{})}
 }
 
-func NewHandler(service *service.Service) *Handler {
-	return &Handler{service: service}
+// Shutdown закрывает долгоживущие потоки обновлений, чтобы остановка сервера
+// не ждала их до таймаута. Обычные запросы дорабатывают как есть.
+func (h *Handler) Shutdown() {
+	h.shutdownOnce.Do(func() { close(h.shutdown) })
 }
 
 func (h *Handler) GetBalance(w http.ResponseWriter, r *http.Request) {
 	user, ok := r.Context().Value("user").(*models.User)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	// Получаем аккаунт пользователя
 	account, err := h.service.GetAccountByUserID(r.Context(), user.ID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	balance, err := h.service.GetBalance(r.Context(), account.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"account_id": account.ID,
-		"balance":    account.Balance,
-		"currency":   "RUB",
-	})
+	json.NewEncoder(w).Encode(balance)
 }
 
 func (h *Handler) TransferMoney(w http.ResponseWriter, r *http.Request) {
 	user, ok := r.Context().Value("user").(*models.User)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	var req struct {
-		ToEmail  string  `json:"to_email"`
-		Amount   float64 `json:"amount"`
-		Currency string  `json:"currency"`
+		To              *models.Recipient `json:"to"`
+		ToEmail         string            `json:"to_email"`
+		ToAccountNumber string            `json:"to_account_number"`
+		BeneficiaryID   *uuid.UUID        `json:"beneficiary_id"`
+		Amount          float64           `json:"amount" validate:"gt=0"`
+		Currency        string            `json:"currency" validate:"required,currency"`
+		Password        string            `json:"password"`
+		models.TransferDetails
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	// Валидация
-	if req.Amount <= 0 {
-		http.Error(w, "Amount must be positive", http.StatusBadRequest)
-		return
+	// to_email и to_account_number — устаревшие формы поля to
+	if req.To == nil {
+		switch {
+		case req.ToAccountNumber != "":
+			req.To = &models.Recipient{Type: models.AliasAccountNumber, Value: req.ToAccountNumber}
+		case req.ToEmail != "":
+			req.To = &models.Recipient{Type: models.AliasEmail, Value: req.ToEmail}
+		}
 	}
 
-	if req.ToEmail == "" {
-		http.Error(w, "Recipient email is required", http.StatusBadRequest)
+	if req.To == nil && req.BeneficiaryID == nil {
+		problem.Error(w, "Recipient or beneficiary is required", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.TransferMoneyByEmail(r.Context(), user.ID, req.ToEmail, req.Amount, req.Currency); err != nil {
-		log.Printf("Transfer error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	var (
+		transferID uuid.UUID
+		err        error
+	)
+	switch {
+	case req.BeneficiaryID != nil:
+		transferID, err = h.service.TransferToBeneficiary(r.Context(), user.ID, *req.BeneficiaryID, req.Amount, req.Currency, req.TransferDetails, req.Password)
+	default:
+		transferID, err = h.service.TransferToRecipient(r.Context(), user.ID, *req.To, req.Amount, req.Currency, req.TransferDetails, req.Password)
+	}
+	if err != nil {
+		slog.WarnContext(r.Context(), "transfer failed", "error", err)
+		writeError(w, err, http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "Transfer successful"})
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":     "Transfer successful",
+		"transfer_id": transferID.String(),
+	})
 }
 func (h *Handler) GetTransfersHistory(w http.ResponseWriter, r *http.Request) {
 	user, ok := r.Context().Value("user").(*models.User)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	// Получаем аккаунт пользователя
 	account, err := h.service.GetAccountByUserID(r.Context(), user.ID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err, http.StatusInternalServerError)
 		return
 	}
 
 	transfers, err := h.service.GetTransfersHistory(r.Context(), account.ID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -105,34 +140,33 @@ func (h *Handler) GetTransfersHistory(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DepositMoney(w http.ResponseWriter, r *http.Request) {
 	user, ok := r.Context().Value("user").(*models.User)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	var req struct {
-		Amount float64 `json:"amount"`
+		Amount float64 `json:"amount" validate:"gt=0"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Получаем аккаунт пользователя
 	account, err := h.service.GetAccountByUserID(r.Context(), user.ID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err, http.StatusInternalServerError)
 		return
 	}
 
 	if err := h.service.DepositMoney(r.Context(), account.ID, req.Amount); err != nil {
-		log.Printf("Deposit error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "deposit failed", "error", err)
+		writeError(w, err, http.StatusInternalServerError)
 		return
 	}
 
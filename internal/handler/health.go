@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"money-transfer-service/internal/cache"
+	"money-transfer-service/internal/migrate"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+// HealthHandler отвечает на проверки оркестратора: /healthz — процесс жив,
+// /readyz — сервис готов принимать трафик
+type HealthHandler struct {
+	db       *sql.DB
+	redis    *cache.RedisClient
+	migrator *migrate.Migrator
+	draining atomic.Bool
+}
+
+func NewHealthHandler(db *sql.DB, redis *cache.RedisClient, migrator *migrate.Migrator) *HealthHandler {
+	return &HealthHandler{db: db, redis: redis, migrator: migrator}
+}
+
+// SetDraining переводит сервис в режим остановки: readyz начинает отвечать 503,
+// чтобы балансировщик перестал присылать новые запросы
+func (h *HealthHandler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// checkResult — публичный ответ проверки: текст ошибок зависимостей только в логе
+type checkResult struct {
+	Status  string `json:"status"`
+	Version *int64 `json:"version,omitempty"`
+}
+
+type healthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks,omitempty"`
+}
+
+func writeHealth(w http.ResponseWriter, status int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Liveness не трогает зависимости: перезапуск процесса не поможет при недоступной базе
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+	defer cancel()
+
+	checks := map[string]checkResult{
+		"postgres": result(ctx, "postgres", h.db.PingContext(ctx)),
+		"redis":    result(ctx, "redis", h.redis.Ping(ctx)),
+	}
+
+	migrations := result(ctx, "migrations", h.migrator.Check(ctx))
+	if version, _, err := h.migrator.Version(ctx); err == nil {
+		migrations.Version = &version
+	}
+	checks["migrations"] = migrations
+
+	resp := healthResponse{Status: "ok", Checks: checks}
+	status := http.StatusOK
+	for _, c := range checks {
+		if c.Status != "ok" {
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	if h.draining.Load() {
+		resp.Status = "draining"
+		status = http.StatusServiceUnavailable
+	}
+	writeHealth(w, status, resp)
+}
+
+func result(ctx context.Context, check string, err error) checkResult {
+	if err != nil {
+		slog.WarnContext(ctx, "readiness check failed", "check", check, "error", err)
+		return checkResult{Status: "error"}
+	}
+	return checkResult{Status: "ok"}
+}
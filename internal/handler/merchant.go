@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+)
+
+func writeMerchantError(w http.ResponseWriter, err error) {
+	writeError(w, err, http.StatusBadRequest)
+}
+
+func (h *Handler) checkoutURL(id uuid.UUID) string {
+	return h.publicURL("/checkout/" + id.String() + "?token=" + h.service.CheckoutToken(id))
+}
+
+func (h *Handler) GetMerchant(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	m, err := h.service.GetMerchant(r.Context(), user.ID)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m)
+}
+
+func (h *Handler) CreateMerchant(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.MerchantRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	m, err := h.service.CreateMerchant(r.Context(), user.ID, req)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(m)
+}
+
+func (h *Handler) UpdateMerchant(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.MerchantRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	m, err := h.service.UpdateMerchant(r.Context(), user.ID, req)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m)
+}
+
+func (h *Handler) CreateCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateCheckoutSessionRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	session, err := h.service.CreateCheckoutSession(r.Context(), user.ID, req)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+	session.CheckoutURL = h.checkoutURL(session.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+func (h *Handler) ListCheckoutSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := h.service.GetCheckoutSessions(r.Context(), user.ID)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+	for i := range sessions {
+		sessions[i].CheckoutURL = h.checkoutURL(sessions[i].ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+func (h *Handler) GetCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	h.checkoutAction(w, r, h.service.GetMerchantCheckoutSession)
+}
+
+func (h *Handler) CompleteCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	h.checkoutAction(w, r, h.service.CompleteCheckoutSession)
+}
+
+func (h *Handler) CancelCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	h.checkoutAction(w, r, h.service.CancelCheckoutSession)
+}
+
+// checkoutAction — общая обвязка для действий мерчанта над одной сессией
+func (h *Handler) checkoutAction(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, userID, id uuid.UUID) (*models.CheckoutSession, error)) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	session, err := action(r.Context(), user.ID, id)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+	session.CheckoutURL = h.checkoutURL(session.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+func (h *Handler) RefundCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RefundRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	refund, err := h.service.RefundCheckoutSession(r.Context(), user.ID, id, req)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
+
+func (h *Handler) ListCheckoutRefunds(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	refunds, err := h.service.GetCheckoutRefunds(r.Context(), user.ID, id)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(refunds)
+}
+
+// GetPublicCheckoutSession — страница оплаты, на которую мерчант перенаправляет плательщика
+func (h *Handler) GetPublicCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.service.GetPublicCheckoutSession(r.Context(), id)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+func (h *Handler) PayCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.PayCheckoutSessionRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	redirect, err := h.service.PayCheckoutSession(r.Context(), user.ID, id, req.Password)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(redirect)
+}
+
+func (h *Handler) AbandonCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.Context().Value("user").(*models.User); !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.AbandonCheckoutSessionRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	redirect, err := h.service.CancelCheckoutByPayer(r.Context(), id, req.Token)
+	if err != nil {
+		writeMerchantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(redirect)
+}
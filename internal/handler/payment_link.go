@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+	"money-transfer-service/internal/qr"
+)
+
+// publicURL строит внешний адрес страницы сервиса от настроенного базового адреса.
+// Host запроса не используется: ссылки и QR-коды кэшируются и не должны зависеть от клиента.
+func (h *Handler) publicURL(path string) string {
+	return strings.TrimRight(h.publicBaseURL, "/") + path
+}
+
+func (h *Handler) paymentLinkURL(code string) string {
+	return h.publicURL("/pay/" + code)
+}
+
+func writePaymentLinkError(w http.ResponseWriter, err error) {
+	writeError(w, err, http.StatusBadRequest)
+}
+
+func (h *Handler) CreatePaymentLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreatePaymentLinkRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	link, err := h.service.CreatePaymentLink(r.Context(), user.ID, req)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	link.URL = h.paymentLinkURL(link.Code)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(link)
+}
+
+func (h *Handler) ListPaymentLinks(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	links, err := h.service.GetPaymentLinks(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	for i := range links {
+		links[i].URL = h.paymentLinkURL(links[i].Code)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
+func (h *Handler) ListPaymentLinkPayments(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	payments, err := h.service.GetPaymentLinkPayments(r.Context(), user.ID, chi.URLParam(r, "code"))
+	if err != nil {
+		writePaymentLinkError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payments)
+}
+
+func (h *Handler) CancelPaymentLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.CancelPaymentLink(r.Context(), user.ID, chi.URLParam(r, "code")); err != nil {
+		writePaymentLinkError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) PayPaymentLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.PayPaymentLinkRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	transferID, err := h.service.PayPaymentLink(r.Context(), user.ID, chi.URLParam(r, "code"), req)
+	if err != nil {
+		writePaymentLinkError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":     "Payment successful",
+		"transfer_id": transferID.String(),
+	})
+}
+
+// GetPublicPaymentLink — публичная страница ссылки, авторизация не нужна
+func (h *Handler) GetPublicPaymentLink(w http.ResponseWriter, r *http.Request) {
+	link, err := h.service.GetPublicPaymentLink(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		writePaymentLinkError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(link)
+}
+
+// GetPaymentLinkQR отдает QR-код со ссылкой: ?format=png|svg&size=256
+func (h *Handler) GetPaymentLinkQR(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+	if _, err := h.service.GetPublicPaymentLink(r.Context(), code); err != nil {
+		writePaymentLinkError(w, err)
+		return
+	}
+
+	size := qr.DefaultSize
+	if v := r.URL.Query().Get("size"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < qr.MinSize || parsed > qr.MaxSize {
+			problem.Error(w, "Invalid size", http.StatusBadRequest)
+			return
+		}
+		size = parsed
+	}
+
+	var (
+		image       []byte
+		contentType string
+		err         error
+	)
+	switch r.URL.Query().Get("format") {
+	case "", "png":
+		image, err = qr.PNG(h.paymentLinkURL(code), size)
+		contentType = "image/png"
+	case "svg":
+		image, err = qr.SVG(h.paymentLinkURL(code), size)
+		contentType = "image/svg+xml"
+	default:
+		problem.Error(w, "Format must be png or svg", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write(image)
+}
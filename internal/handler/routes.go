@@ -1,25 +1,172 @@
 package handler
 
 import (
+	"net/http"
+	"path/filepath"
+
 	"github.com/go-chi/chi"
+	"money-transfer-service/internal/logging"
+	"money-transfer-service/internal/metrics"
 	"money-transfer-service/internal/middleware"
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/openapi"
+	"money-transfer-service/internal/problem"
 	"money-transfer-service/internal/repository"
+	"money-transfer-service/internal/tracing"
 )
 
-func (h *Handler) Routes(authHandler *AuthHandler, repo *repository.Repository) chi.Router {
+// Routes собирает роутер всего HTTP API. staticDir — каталог со статикой веб-интерфейса.
+func (h *Handler) Routes(authHandler *AuthHandler, health *HealthHandler, repo *repository.Repository, staticDir string) chi.Router {
 	r := chi.NewRouter()
 
+	// X-Request-ID (должно быть первым, чтобы идентификатор был во всех логах запроса)
+	r.Use(logging.RequestIDMiddleware)
+
+	// Трейс запроса: продолжает входящий traceparent или начинает новый
+	r.Use(tracing.Middleware)
+
+	// Лог запросов
+	r.Use(logging.Middleware)
+
+	// Счетчики и длительность запросов по шаблонам маршрутов
+	r.Use(metrics.Middleware)
+
+	// Ошибки маршрутизации в том же формате problem+json, что и ошибки обработчиков
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		problem.Error(w, "no route for "+r.URL.Path, http.StatusNotFound)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		problem.Error(w, r.Method+" is not allowed for "+r.URL.Path, http.StatusMethodNotAllowed)
+	})
+
+	fileServer := http.FileServer(http.Dir(staticDir))
+
+	// Проверки для оркестратора
+	r.Get("/healthz", health.Liveness)
+	r.Get("/readyz", health.Readiness)
+	r.Handle("/metrics", metrics.Handler())
+
+	// Спецификация API и Swagger UI
+	r.Get(openapi.SpecPath, openapi.Handler().ServeHTTP)
+	r.Get("/docs", http.RedirectHandler(openapi.DocsPath, http.StatusMovedPermanently).ServeHTTP)
+	r.Handle(openapi.DocsPath+"*", openapi.UI())
+
+	// Статические файлы
+	r.Handle("/static/*", http.StripPrefix("/static/", fileServer))
+
+	// Главная страница
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.ServeFile(w, r, filepath.Join(staticDir, "index.html"))
+			return
+		}
+		fileServer.ServeHTTP(w, r)
+	})
+
 	// Public routes
 	r.Post("/auth/register", authHandler.Register)
 	r.Post("/auth/login", authHandler.Login)
 
-	// Protected routes
-	r.Group(func(r chi.Router) {
+	// Публичные страницы ссылок на оплату
+	r.Get("/pay/{code}", h.GetPublicPaymentLink)
+	r.Get("/pay/{code}/qr", h.GetPaymentLinkQR)
+	r.Get("/checkout/{id}", h.GetPublicCheckoutSession)
+
+	// Protected routes - создаем подроутер с middleware аутентификации
+	r.Route("/api", func(r chi.Router) {
+		// Middleware аутентификации только для API routes
 		r.Use(middleware.AuthMiddleware(repo))
 
-		r.Get("/api/balance", h.GetBalance)
-		r.Post("/api/transfer", h.TransferMoney)
-		r.Get("/api/transfers", h.GetTransfersHistory)
+		r.Get("/balance", h.GetBalance)
+		r.Get("/updates", h.StreamUpdates)
+		r.Post("/transfer", h.TransferMoney)
+		r.Post("/deposit", h.DepositMoney)
+		r.Get("/transfers", h.GetTransfersHistory)
+		r.Get("/transfers/search", h.SearchTransfers)
+		r.Put("/transfers/{id}/tags", h.SetTransferTags)
+		r.Post("/transfers/batch", h.CreateTransferBatch)
+		r.Get("/transfers/batch/{id}", h.GetTransferBatch)
+
+		r.Get("/beneficiaries", h.ListBeneficiaries)
+		r.Post("/beneficiaries", h.CreateBeneficiary)
+		r.Put("/beneficiaries/{id}", h.UpdateBeneficiary)
+		r.Delete("/beneficiaries/{id}", h.DeleteBeneficiary)
+		r.Get("/recipients/confirm", h.ConfirmRecipient)
+		r.Get("/aliases", h.GetAliases)
+		r.Post("/aliases", h.CreateAlias)
+		r.Post("/aliases/{id}/verify", h.VerifyAlias)
+		r.Delete("/aliases/{id}", h.DeleteAlias)
+
+		r.Get("/groups", h.ListExpenseGroups)
+		r.Post("/groups", h.CreateExpenseGroup)
+		r.Get("/groups/{id}", h.GetExpenseGroup)
+		r.Post("/groups/{id}/members", h.AddGroupMember)
+		r.Get("/groups/{id}/expenses", h.ListGroupExpenses)
+		r.Post("/groups/{id}/expenses", h.AddExpense)
+		r.Get("/groups/{id}/balances", h.GetGroupBalances)
+		r.Post("/groups/{id}/settle", h.SettleUp)
+
+		r.Get("/escrow", h.ListEscrows)
+		r.Post("/escrow", h.CreateEscrow)
+		r.Get("/escrow/{id}", h.GetEscrow)
+		r.Post("/escrow/{id}/confirm", h.ConfirmEscrow)
+		r.Post("/escrow/{id}/cancel", h.CancelEscrow)
+		r.Post("/escrow/{id}/dispute", h.DisputeEscrow)
+
+		r.Get("/authorizations", h.ListAuthorizations)
+		r.Post("/authorizations", h.Authorize)
+		r.Get("/authorizations/{id}", h.GetAuthorization)
+		r.Post("/authorizations/{id}/capture", h.CaptureAuthorization)
+		r.Post("/authorizations/{id}/void", h.VoidAuthorization)
+
+		r.Get("/payment-links", h.ListPaymentLinks)
+		r.Post("/payment-links", h.CreatePaymentLink)
+		r.Get("/payment-links/{code}/payments", h.ListPaymentLinkPayments)
+		r.Post("/payment-links/{code}/pay", h.PayPaymentLink)
+		r.Delete("/payment-links/{code}", h.CancelPaymentLink)
+
+		r.Get("/merchant", h.GetMerchant)
+		r.Post("/merchant", h.CreateMerchant)
+		r.Put("/merchant", h.UpdateMerchant)
+		r.Get("/merchant/checkout-sessions", h.ListCheckoutSessions)
+		r.Post("/merchant/checkout-sessions", h.CreateCheckoutSession)
+		r.Get("/merchant/checkout-sessions/{id}", h.GetCheckoutSession)
+		r.Post("/merchant/checkout-sessions/{id}/complete", h.CompleteCheckoutSession)
+		r.Post("/merchant/checkout-sessions/{id}/cancel", h.CancelCheckoutSession)
+		r.Get("/merchant/checkout-sessions/{id}/refunds", h.ListCheckoutRefunds)
+		r.Post("/merchant/checkout-sessions/{id}/refunds", h.RefundCheckoutSession)
+		r.Post("/checkout/{id}/pay", h.PayCheckoutSession)
+		r.Post("/checkout/{id}/cancel", h.AbandonCheckoutSession)
+
+		r.Get("/webhooks", h.ListWebhookEndpoints)
+		r.Post("/webhooks", h.CreateWebhookEndpoint)
+		r.Get("/webhooks/events", h.ListWebhookEvents)
+		r.Get("/webhooks/{id}", h.GetWebhookEndpoint)
+		r.Put("/webhooks/{id}", h.UpdateWebhookEndpoint)
+		r.Delete("/webhooks/{id}", h.DeleteWebhookEndpoint)
+		r.Post("/webhooks/{id}/rotate-secret", h.RotateWebhookSecret)
+		r.Post("/webhooks/{id}/test", h.SendTestWebhook)
+		r.Get("/webhooks/{id}/deliveries", h.ListWebhookDeliveries)
+		r.Post("/webhooks/{id}/deliveries/{deliveryID}/redeliver", h.RedeliverWebhook)
+
+		r.Get("/notifications", h.GetNotifications)
+		r.Get("/notifications/preferences", h.GetNotificationPreferences)
+		r.Put("/notifications/preferences", h.UpdateNotificationPreferences)
+
+		r.Get("/account/status-history", h.GetAccountStatusHistory)
+		r.Post("/account/freeze", h.FreezeAccount)
+		r.Post("/account/unfreeze", h.UnfreezeAccount)
+		r.Post("/account/close", h.CloseAccount)
+
+		// Администрирование счетов
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.RequireRole(models.RoleAdmin))
+
+			r.Get("/accounts/{id}", h.AdminGetAccount)
+			r.Put("/accounts/{id}/status", h.AdminSetAccountStatus)
+			r.Get("/accounts/{id}/status-history", h.AdminGetAccountStatusHistory)
+			r.Post("/escrow/{id}/resolve", h.AdminResolveEscrow)
+		})
 	})
 
 	return r
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+	"money-transfer-service/internal/realtime"
+)
+
+const streamHeartbeat = 25 * time.Second
+
+// StreamUpdates — поток Server-Sent Events с балансом и входящими переводами.
+// Сразу после подключения отправляется текущий баланс.
+func (h *Handler) StreamUpdates(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		problem.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	// Поток живет дольше WriteTimeout сервера, снимаем ограничение для этого соединения
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		problem.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	account, err := h.service.GetAccountByUserID(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	balance, err := h.service.GetBalance(r.Context(), account.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	// Подписываемся до отправки снимка, чтобы не пропустить изменения между ними
+	updates, unsubscribe := h.service.SubscribeUpdates(user.ID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	data, _ := json.Marshal(balance)
+	writeEvent(w, realtime.MessageBalance, data)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.shutdown:
+			// Сервер останавливается: клиент переподключится к другому экземпляру
+			return
+		case msg := <-updates:
+			writeEvent(w, msg.Type, msg.Data)
+			flusher.Flush()
+		case <-heartbeat.C:
+			// Комментарий не дает прокси закрыть неактивное соединение
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event string, data []byte) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
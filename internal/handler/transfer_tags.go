@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+)
+
+func (h *Handler) SetTransferTags(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	transferID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Error(w, "Invalid transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetTransferTagsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	account, err := h.service.GetAccountByUserID(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	tags, err := h.service.SetTransferTags(r.Context(), account.ID, transferID, req.Tags)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"transfer_id": transferID,
+		"tags":        tags,
+	})
+}
+
+func (h *Handler) SearchTransfers(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		problem.Error(w, "Search query is required", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.service.GetAccountByUserID(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	transfers, err := h.service.SearchTransfers(r.Context(), account.ID, query)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if transfers == nil {
+		transfers = []models.Transfer{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfers)
+}
@@ -0,0 +1,142 @@
+// Package logging настраивает slog: JSON-вывод, уровень из конфигурации, поля запроса
+// из контекста (request_id, user_id, route, trace_id) и маскирование персональных данных.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi"
+	"go.opentelemetry.io/otel/trace"
+
+	"money-transfer-service/internal/config"
+)
+
+// Setup создает логгер по конфигурации и делает его логгером по умолчанию.
+// Вызовы стандартного log тоже проходят через него с уровнем INFO.
+func Setup(cfg config.LogConfig) *slog.Logger {
+	logger := New(os.Stdout, cfg)
+	slog.SetDefault(logger)
+	return logger
+}
+
+func New(w io.Writer, cfg config.LogConfig) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	var h slog.Handler
+	if strings.EqualFold(cfg.Format, "text") {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(contextHandler{h})
+}
+
+type ctxKey int
+
+const (
+	requestKey ctxKey = iota
+	attrsKey
+)
+
+// requestInfo создается один раз на запрос. Пользователь становится известен позже,
+// в AuthMiddleware, поэтому поле изменяемое: так его видит и итоговая строка лога запроса.
+type requestInfo struct {
+	id string
+
+	mu     sync.Mutex
+	userID string
+}
+
+func withRequest(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestKey, &requestInfo{id: id})
+}
+
+// RequestID возвращает идентификатор текущего запроса или пустую строку
+func RequestID(ctx context.Context) string {
+	if info, ok := ctx.Value(requestKey).(*requestInfo); ok {
+		return info.id
+	}
+	return ""
+}
+
+// SetUserID привязывает пользователя к логам текущего запроса
+func SetUserID(ctx context.Context, userID string) {
+	if info, ok := ctx.Value(requestKey).(*requestInfo); ok {
+		info.mu.Lock()
+		info.userID = userID
+		info.mu.Unlock()
+	}
+}
+
+// With добавляет поля ко всем записям, сделанным с этим контекстом
+// (например, consumer для обработчиков событий)
+func With(ctx context.Context, args ...any) context.Context {
+	attrs, _ := ctx.Value(attrsKey).([]slog.Attr)
+	attrs = append(attrs[:len(attrs):len(attrs)], argsToAttrs(args)...)
+	return context.WithValue(ctx, attrsKey, attrs)
+}
+
+func argsToAttrs(args []any) []slog.Attr {
+	var r slog.Record
+	r.Add(args...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// contextHandler дописывает к записи поля из контекста и маскирует текст сообщения
+// (атрибуты маскирует ReplaceAttr)
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Message = Redact(r.Message)
+	if ctx != nil {
+		r.AddAttrs(contextAttrs(ctx)...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+func contextAttrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+	if info, ok := ctx.Value(requestKey).(*requestInfo); ok {
+		attrs = append(attrs, slog.String("request_id", info.id))
+		info.mu.Lock()
+		userID := info.userID
+		info.mu.Unlock()
+		if userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+	}
+	if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+		attrs = append(attrs, slog.String("route", rctx.RoutePattern()))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+	}
+	if extra, ok := ctx.Value(attrsKey).([]slog.Attr); ok {
+		attrs = append(attrs, extra...)
+	}
+	return attrs
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Входящий идентификатор принимаем, только если он не сломает логи
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestIDMiddleware берет X-Request-ID из запроса (например, от балансировщика) или создает новый,
+// кладет его в контекст и возвращает клиенту в ответе
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, id := WithRequestID(r.Context(), r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// WithRequestID начинает контекст запроса с переданным идентификатором или, если он пустой
+// или недопустимый, с новым. Для транспортов помимо HTTP (gRPC).
+func WithRequestID(ctx context.Context, id string) (context.Context, string) {
+	if !validRequestID.MatchString(id) {
+		id = uuid.NewString()
+	}
+	return withRequest(ctx, id), id
+}
+
+// Middleware пишет по строке на запрос: маршрут, статус, размер ответа и длительность.
+// Ставится после tracing.Middleware, чтобы запись несла trace_id, а спан — request_id.
+// Строка запроса не пишется: в ней бывают адреса получателей.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx := r.Context()
+		if id := RequestID(ctx); id != "" {
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("http.request_id", id))
+		}
+
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(ctx, level, "request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
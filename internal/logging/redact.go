@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@([A-Za-z0-9\-]+\.)+[A-Za-z]{2,}`)
+	// Телефоны в формате E.164, как их хранят псевдонимы
+	phonePattern = regexp.MustCompile(`\+[1-9]\d{9,14}\b`)
+	// JWT, заголовок Authorization и секреты вебхуков
+	tokenPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`),
+		regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/\-]+=*`),
+		regexp.MustCompile(`whsec_[0-9a-f]+`),
+	}
+)
+
+// Значения с такими ключами не пишутся никогда
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"authorization": true,
+	"secret":        true,
+	"api_key":       true,
+	"code":          true,
+}
+
+// Redact скрывает в строке адреса почты (остается домен), телефоны (остаются две последние
+// цифры) и токены
+func Redact(s string) string {
+	if strings.Contains(s, "@") {
+		s = emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+			return "***" + email[strings.LastIndex(email, "@"):]
+		})
+	}
+	if strings.Contains(s, "+") {
+		s = phonePattern.ReplaceAllStringFunc(s, func(phone string) string {
+			return "+***" + phone[len(phone)-2:]
+		})
+	}
+	for _, p := range tokenPatterns {
+		s = p.ReplaceAllString(s, redacted)
+	}
+	return s
+}
+
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s := a.Value.String(); s != "" {
+			a.Value = slog.StringValue(Redact(s))
+		}
+	case slog.KindAny:
+		// Тексты ошибок часто содержат входные данные, например адрес получателя
+		if err, ok := a.Value.Any().(error); ok {
+			a.Value = slog.StringValue(Redact(err.Error()))
+		}
+	}
+	return a
+}
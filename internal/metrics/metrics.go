@@ -0,0 +1,170 @@
+// Package metrics — метрики Prometheus, отдаются на /metrics
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "money_transfer"
+
+// Исходы переводов для метрик
+const (
+	OutcomeSuccess           = "success"
+	OutcomeInsufficientFunds = "insufficient_funds"
+	OutcomeNotFound          = "not_found"
+	OutcomeAccountBlocked    = "account_blocked"
+	OutcomeLimitExceeded     = "limit_exceeded"
+	OutcomeRejected          = "rejected"
+	OutcomeError             = "error"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "gRPC calls by service, method and status code.",
+	}, []string{"service", "method", "code"})
+
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "gRPC call latency by service and method; for streams — the stream lifetime.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	grpcStreams = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "grpc_active_streams",
+		Help:      "Open gRPC server streams by service and method.",
+	}, []string{"service", "method"})
+
+	transfers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Transfer attempts by currency and outcome.",
+	}, []string{"currency", "outcome"})
+
+	transferVolume = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfer_amount_total",
+		Help:      "Sum of transfer amounts in the transfer currency, by currency and outcome.",
+	}, []string{"currency", "outcome"})
+
+	fxCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fx_rate_cache_lookups_total",
+		Help:      "Exchange rate lookups in Redis by result (hit or miss).",
+	}, []string{"result"})
+
+	fxCacheHits, fxCacheMisses atomic.Int64
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "fx_rate_cache_hit_ratio",
+		Help:      "Share of exchange rate lookups served from Redis since start.",
+	}, func() float64 {
+		hits, misses := fxCacheHits.Load(), fxCacheMisses.Load()
+		if hits+misses == 0 {
+			return 0
+		}
+		return float64(hits) / float64(hits+misses)
+	})
+)
+
+// Handler отдает метрики в формате Prometheus
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDB добавляет статистику пула соединений sql.DB
+func RegisterDB(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Middleware считает запросы и их длительность. Маршрут берется из шаблона chi
+// (например, /api/webhooks/{id}), чтобы не плодить метки по идентификаторам.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		httpRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		httpDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// ObserveGRPC учитывает завершенный вызов. fullMethod — вида /moneytransfer.v1.FXService/GetRate.
+func ObserveGRPC(fullMethod, code string, d time.Duration) {
+	service, method := splitMethod(fullMethod)
+	grpcRequests.WithLabelValues(service, method, code).Inc()
+	grpcDuration.WithLabelValues(service, method).Observe(d.Seconds())
+}
+
+// TrackGRPCStream отмечает открытый поток; возвращенную функцию нужно вызвать при его закрытии
+func TrackGRPCStream(fullMethod string) func() {
+	gauge := grpcStreams.WithLabelValues(splitMethod(fullMethod))
+	gauge.Inc()
+	return gauge.Dec
+}
+
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", fullMethod
+	}
+	return service, method
+}
+
+// ObserveTransfer учитывает попытку перевода
+func ObserveTransfer(currency string, amount float64, outcome string) {
+	transfers.WithLabelValues(currency, outcome).Inc()
+	transferVolume.WithLabelValues(currency, outcome).Add(amount)
+}
+
+// ObserveRateCache учитывает обращение к кэшу курсов
+func ObserveRateCache(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+		fxCacheHits.Add(1)
+	} else {
+		fxCacheMisses.Add(1)
+	}
+	fxCacheLookups.WithLabelValues(result).Inc()
+}
@@ -5,10 +5,9 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/golang-jwt/jwt"
-	"github.com/google/uuid"
-
 	"money-transfer-service/internal/auth"
+	"money-transfer-service/internal/logging"
+	"money-transfer-service/internal/problem"
 	"money-transfer-service/internal/repository"
 )
 
@@ -17,50 +16,33 @@ func AuthMiddleware(repo *repository.Repository) func(http.Handler) http.Handler
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
+				problem.Error(w, "Authorization header required", http.StatusUnauthorized)
 				return
 			}
 
 			parts := strings.Split(authHeader, " ")
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "Authorization header format must be: Bearer {token}", http.StatusUnauthorized)
-				return
-			}
-
-			token, err := auth.ParseJWT(parts[1])
-			if err != nil || !token.Valid {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
-				return
-			}
-
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-				return
-			}
-
-			userIDStr, ok := claims["user_id"].(string)
-			if !ok {
-				http.Error(w, "Invalid user ID in token", http.StatusUnauthorized)
+				problem.Error(w, "Authorization header format must be: Bearer {token}", http.StatusUnauthorized)
 				return
 			}
 
-			userID, err := uuid.Parse(userIDStr)
+			userID, err := auth.UserIDFromToken(parts[1])
 			if err != nil {
-				http.Error(w, "Invalid user ID format", http.StatusUnauthorized)
+				problem.Error(w, "Invalid token", http.StatusUnauthorized)
 				return
 			}
 
 			user, err := repo.GetUserByID(r.Context(), userID)
 			if err != nil {
-				http.Error(w, "Error finding user", http.StatusInternalServerError)
+				problem.Error(w, "Error finding user", http.StatusInternalServerError)
 				return
 			}
 			if user == nil {
-				http.Error(w, "User not found", http.StatusUnauthorized)
+				problem.Error(w, "User not found", http.StatusUnauthorized)
 				return
 			}
 
+			logging.SetUserID(r.Context(), user.ID.String())
 			ctx := context.WithValue(r.Context(), "user", user)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/problem"
+)
+
+// RequireRole пропускает только пользователей с указанной ролью.
+// Должен стоять после AuthMiddleware.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value("user").(*models.User)
+			if !ok {
+				problem.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if user.Role != role {
+				problem.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
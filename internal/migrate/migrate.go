@@ -0,0 +1,350 @@
+// Package migrate применяет версионированные миграции схемы, встроенные в бинарник.
+// Файлы лежат в migrations/ и называются NNNN_name.up.sql / NNNN_name.down.sql.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var files embed.FS
+
+// lockKey — ключ advisory-блокировки, чтобы миграции не выполнялись параллельно
+// с нескольких экземпляров
+const lockKey = 7245002
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// ErrSchemaVersion — схема базы не совпадает с версией, которую ожидает бинарник
+var ErrSchemaVersion = errors.New("unexpected schema version")
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status — состояние одной миграции для команды status
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New загружает встроенные миграции. Для каждой версии обязательны оба файла.
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		m := fileName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+		body, err := fs.ReadFile(fsys, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s and %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest — версия последней встроенной миграции, ее ожидает сервер
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version BIGINT PRIMARY KEY,
+            name VARCHAR(255) NOT NULL,
+            dirty BOOLEAN NOT NULL DEFAULT FALSE,
+            applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+        )
+    `)
+	return err
+}
+
+// withLock выполняет fn на отдельном соединении под сессионной advisory-блокировкой.
+// Блокировка держится на соединении, поэтому все запросы идут через conn.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// version возвращает последнюю записанную версию и признак незавершенной миграции
+func version(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}) (int64, bool, error) {
+	var (
+		v     int64
+		dirty bool
+	)
+	err := q.QueryRowContext(ctx, `
+        SELECT version, dirty FROM schema_migrations ORDER BY version DESC LIMIT 1
+    `).Scan(&v, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return v, dirty, err
+}
+
+// Version — текущая версия схемы. Если таблицы миграций еще нет, версия 0.
+func (m *Migrator) Version(ctx context.Context) (int64, bool, error) {
+	var exists bool
+	err := m.db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return 0, false, err
+	}
+	return version(ctx, m.db)
+}
+
+// Check возвращает ErrSchemaVersion, если схема не на последней версии или помечена dirty
+func (m *Migrator) Check(ctx context.Context) error {
+	v, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w: version %d is dirty, fix it and run migrate force", ErrSchemaVersion, v)
+	}
+	if v != m.Latest() {
+		return fmt.Errorf("%w: database is at %d, expected %d", ErrSchemaVersion, v, m.Latest())
+	}
+	return nil
+}
+
+// Up применяет все неприменные миграции по порядку и возвращает их число
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("version %d is dirty, fix it and run migrate force", current)
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down откатывает steps последних примененных миграций
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("version %d is dirty, fix it and run migrate force", current)
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// apply выполняет одну миграцию в транзакции. Перед началом версия помечается dirty
+// отдельной записью: если миграция упадет, пометка останется и потребует ручного force.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, up bool) error {
+	_, err := conn.ExecContext(ctx, `
+        INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, TRUE)
+        ON CONFLICT (version) DO UPDATE SET dirty = TRUE
+    `, migration.Version, migration.Name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	script := migration.Up
+	if !up {
+		script = migration.Down
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, `
+            UPDATE schema_migrations SET dirty = FALSE, applied_at = CURRENT_TIMESTAMP WHERE version = $1
+        `, migration.Version)
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Force записывает версию без выполнения миграций и снимает пометку dirty.
+// Нужен после ручного исправления схемы, когда миграция упала на полпути.
+func (m *Migrator) Force(ctx context.Context, v int64) error {
+	var migration *Migration
+	for i := range m.migrations {
+		if m.migrations[i].Version == v {
+			migration = &m.migrations[i]
+		}
+	}
+	if migration == nil && v != 0 {
+		return fmt.Errorf("unknown migration version %d", v)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version > $1", v); err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if mg.Version > v {
+				break
+			}
+			_, err := tx.ExecContext(ctx, `
+                INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, FALSE)
+                ON CONFLICT (version) DO UPDATE SET dirty = FALSE
+            `, mg.Version, mg.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// Status перечисляет все встроенные миграции с отметкой о применении
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	result := make([]Status, 0, len(m.migrations))
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		rows, err := conn.QueryContext(ctx, "SELECT version, dirty, applied_at FROM schema_migrations")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		applied := make(map[int64]Status)
+		for rows.Next() {
+			var s Status
+			if err := rows.Scan(&s.Version, &s.Dirty, &s.AppliedAt); err != nil {
+				return err
+			}
+			s.Applied = true
+			applied[s.Version] = s
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			s := applied[migration.Version]
+			s.Version = migration.Version
+			s.Name = migration.Name
+			result = append(result, s)
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,31 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS user_devices;
+DROP TABLE IF EXISTS event_consumer_offsets;
+DROP TABLE IF EXISTS outbox_events;
+DROP SEQUENCE IF EXISTS outbox_event_sequence;
+DROP TABLE IF EXISTS checkout_refunds;
+DROP TABLE IF EXISTS checkout_sessions;
+DROP TABLE IF EXISTS merchants;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
+DROP TABLE IF EXISTS payment_link_payments;
+DROP TABLE IF EXISTS payment_links;
+DROP TABLE IF EXISTS alias_ownership_changes;
+DROP TABLE IF EXISTS aliases;
+DROP TABLE IF EXISTS authorizations;
+DROP TABLE IF EXISTS escrow_transfers;
+DROP TABLE IF EXISTS holds;
+DROP TABLE IF EXISTS group_settlements;
+DROP TABLE IF EXISTS expense_shares;
+DROP TABLE IF EXISTS expenses;
+DROP TABLE IF EXISTS expense_group_members;
+DROP TABLE IF EXISTS expense_groups;
+DROP TABLE IF EXISTS transfer_batch_items;
+DROP TABLE IF EXISTS transfer_batches;
+DROP TABLE IF EXISTS beneficiaries;
+DROP TABLE IF EXISTS transfer_tags;
+DROP TABLE IF EXISTS transfers;
+DROP TABLE IF EXISTS account_status_history;
+DROP TABLE IF EXISTS accounts;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,437 @@
+-- Начальная схема, перенесенная из init.sql. В базах, созданных первой версией init.sql,
+-- уже есть users, accounts и transfers без новых колонок: CREATE TABLE IF NOT EXISTS их
+-- пропускает, поэтому недостающие колонки добавляются отдельно после каждой из этих таблиц.
+-- На новой базе эти ALTER ничего не меняют.
+
+-- Создаем таблицу пользователей
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'user',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+
+-- Создаем таблицу счетов
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id),
+    account_number VARCHAR(20) NOT NULL UNIQUE, -- 20 цифр, последняя — контрольная по алгоритму Луна
+    balance DECIMAL(15, 2) DEFAULT 0.00,
+    held_balance DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'frozen', 'debit_blocked', 'credit_blocked', 'closed'))
+);
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS account_number VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS held_balance DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'frozen', 'debit_blocked', 'credit_blocked', 'closed'));
+
+-- Старым счетам выдаются номера в том же формате, что у accountnumber.Generate:
+-- 40817810, 11 случайных цифр и контрольная цифра Луна
+DO $$
+DECLARE
+    acc RECORD;
+    body TEXT;
+    total INT;
+    digit INT;
+BEGIN
+    FOR acc IN SELECT id FROM accounts WHERE account_number IS NULL LOOP
+        LOOP
+            body := '40817810' || lpad(floor(random() * 1e11)::BIGINT::TEXT, 11, '0');
+            total := 0;
+            FOR i IN 0..length(body) - 1 LOOP
+                digit := substr(body, length(body) - i, 1)::INT;
+                IF i % 2 = 0 THEN
+                    digit := digit * 2;
+                    IF digit > 9 THEN
+                        digit := digit - 9;
+                    END IF;
+                END IF;
+                total := total + digit;
+            END LOOP;
+            body := body || ((10 - total % 10) % 10)::TEXT;
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM accounts WHERE account_number = body);
+        END LOOP;
+        UPDATE accounts SET account_number = body WHERE id = acc.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE accounts ALTER COLUMN account_number SET NOT NULL;
+-- Имя совпадает с ограничением UNIQUE из CREATE TABLE, поэтому на новой базе индекс не дублируется
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_account_number_key ON accounts (account_number);
+
+-- История смены статусов счетов: кто и почему
+CREATE TABLE IF NOT EXISTS account_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    old_status VARCHAR(20) NOT NULL,
+    new_status VARCHAR(20) NOT NULL,
+    changed_by UUID NOT NULL REFERENCES users(id),
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_status_history_account ON account_status_history (account_id, created_at);
+
+-- Создаем таблицу переводов
+CREATE TABLE IF NOT EXISTS transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_account_id UUID REFERENCES accounts(id),
+    to_account_id UUID REFERENCES accounts(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    memo TEXT,
+    reference VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE transfers
+    ADD COLUMN IF NOT EXISTS memo TEXT,
+    ADD COLUMN IF NOT EXISTS reference VARCHAR(100);
+
+-- Личные теги переводов, видны только владельцу счета
+CREATE TABLE IF NOT EXISTS transfer_tags (
+    transfer_id UUID NOT NULL REFERENCES transfers(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (transfer_id, account_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_tags_account ON transfer_tags (account_id, tag);
+CREATE INDEX IF NOT EXISTS idx_transfers_text ON transfers
+    USING GIN (to_tsvector('simple', COALESCE(memo, '') || ' ' || COALESCE(reference, '')));
+
+-- Создаем таблицу сохраненных получателей
+CREATE TABLE IF NOT EXISTS beneficiaries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    nickname VARCHAR(100) NOT NULL,
+    is_favorite BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, account_id)
+);
+
+-- Пакетные переводы и их построчные результаты
+CREATE TABLE IF NOT EXISTS transfer_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    mode VARCHAR(20) NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    total_lines INTEGER NOT NULL,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS transfer_batch_items (
+    batch_id UUID NOT NULL REFERENCES transfer_batches(id) ON DELETE CASCADE,
+    line_no INTEGER NOT NULL,
+    to_email VARCHAR(255) NOT NULL,
+    to_account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    memo TEXT,
+    reference VARCHAR(100),
+    status VARCHAR(20) NOT NULL,
+    transfer_id UUID REFERENCES transfers(id),
+    error TEXT,
+    PRIMARY KEY (batch_id, line_no)
+);
+
+-- Группы совместных расходов
+CREATE TABLE IF NOT EXISTS expense_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS expense_group_members (
+    group_id UUID NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS expenses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    paid_by UUID NOT NULL REFERENCES users(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    split_type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS expense_shares (
+    expense_id UUID NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    PRIMARY KEY (expense_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS group_settlements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    from_user_id UUID NOT NULL REFERENCES users(id),
+    to_user_id UUID NOT NULL REFERENCES users(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    transfer_id UUID NOT NULL REFERENCES transfers(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Удержания средств: сумма остается на счете, но недоступна для трат
+CREATE TABLE IF NOT EXISTS holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_active ON holds (expires_at) WHERE status = 'active';
+
+-- Условные переводы через удержание
+CREATE TABLE IF NOT EXISTS escrow_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_account_id UUID NOT NULL REFERENCES accounts(id),
+    to_account_id UUID NOT NULL REFERENCES accounts(id),
+    hold_id UUID NOT NULL REFERENCES holds(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    memo TEXT,
+    status VARCHAR(20) NOT NULL,
+    resolution VARCHAR(20),
+    sender_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    recipient_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    deadline TIMESTAMPTZ NOT NULL,
+    transfer_id UUID REFERENCES transfers(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_escrow_deadline ON escrow_transfers (deadline) WHERE status = 'held';
+
+-- Двухфазные списания: авторизация, списание, отмена
+CREATE TABLE IF NOT EXISTS authorizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hold_id UUID NOT NULL REFERENCES holds(id),
+    payer_account_id UUID NOT NULL REFERENCES accounts(id),
+    payee_account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    captured_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    currency VARCHAR(3) NOT NULL,
+    memo TEXT,
+    status VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    transfer_id UUID REFERENCES transfers(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_authorizations_expiry ON authorizations (expires_at) WHERE status = 'authorized';
+
+-- Справочник псевдонимов: телефон, имя пользователя
+CREATE TABLE IF NOT EXISTS aliases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('phone', 'username')),
+    value VARCHAR(64) NOT NULL,
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    code_hash VARCHAR(64),
+    code_expires_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    verified_at TIMESTAMP,
+    UNIQUE (user_id, type, value)
+);
+
+-- Подтвержденный псевдоним принадлежит только одному пользователю
+CREATE UNIQUE INDEX IF NOT EXISTS idx_aliases_verified ON aliases (type, value) WHERE verified;
+
+-- Журнал смены владельца (например, номер телефона перешел к другому абоненту)
+CREATE TABLE IF NOT EXISTS alias_ownership_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type VARCHAR(20) NOT NULL,
+    value VARCHAR(64) NOT NULL,
+    old_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Ссылки и QR-коды для получения денег
+CREATE TABLE IF NOT EXISTS payment_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(16) NOT NULL UNIQUE,
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(15, 2) CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    memo TEXT,
+    single_use BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL,
+    payments_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_links_account ON payment_links (account_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS payment_link_payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES payment_links(id),
+    payer_account_id UUID NOT NULL REFERENCES accounts(id),
+    transfer_id UUID NOT NULL REFERENCES transfers(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_link_payments_link ON payment_link_payments (link_id);
+
+-- Эндпоинты вебхуков и очередь доставки событий
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    events TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_user ON webhook_endpoints (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    endpoint_id UUID NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    redelivery_of UUID REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+    next_attempt_at TIMESTAMPTZ,
+    last_status_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id, created_at DESC);
+
+-- Мерчанты и сессии оплаты
+CREATE TABLE IF NOT EXISTS merchants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    webhook_endpoint_id UUID REFERENCES webhook_endpoints(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS checkout_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    amount_rub DECIMAL(15, 2),
+    currency VARCHAR(3) NOT NULL,
+    description TEXT,
+    reference VARCHAR(100),
+    status VARCHAR(20) NOT NULL,
+    success_url TEXT NOT NULL,
+    cancel_url TEXT NOT NULL,
+    payer_account_id UUID REFERENCES accounts(id),
+    transfer_id UUID REFERENCES transfers(id),
+    refunded_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    paid_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkout_sessions_merchant ON checkout_sessions (merchant_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS checkout_refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES checkout_sessions(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    amount_rub DECIMAL(15, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    reason TEXT,
+    transfer_id UUID NOT NULL REFERENCES transfers(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Transactional outbox: доменные события пишутся в одной транзакции с изменением данных.
+-- sequence присваивается при публикации и задает порядок потока для потребителей.
+CREATE SEQUENCE IF NOT EXISTS outbox_event_sequence;
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    sequence BIGINT UNIQUE,
+    event_id UUID NOT NULL UNIQUE,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox_events (id) WHERE published_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS event_consumer_offsets (
+    consumer VARCHAR(100) PRIMARY KEY,
+    last_sequence BIGINT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Устройства, с которых входили пользователи: вход с нового устройства порождает уведомление
+CREATE TABLE IF NOT EXISTS user_devices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    fingerprint VARCHAR(64) NOT NULL,
+    user_agent TEXT NOT NULL,
+    last_ip VARCHAR(45) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, fingerprint)
+);
+
+-- Настройки уведомлений: язык и каналы по видам уведомлений
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id),
+    language VARCHAR(2) NOT NULL DEFAULT 'ru',
+    channels JSONB NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Журнал доставки уведомлений: одна запись на событие, пользователя и канал
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    event_id UUID NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    channel VARCHAR(10) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('sent', 'failed', 'skipped')),
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP,
+    UNIQUE (event_id, user_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC);
@@ -0,0 +1,4 @@
+-- Версия 2 раньше добавляла тестовых пользователей alice и bob. Сид перенесен в команду
+-- migrate seed-dev, а номер версии остается занятым, чтобы не расходиться с базами,
+-- где она уже применена. Пользователей в таких базах миграция не трогает.
+SELECT 1;
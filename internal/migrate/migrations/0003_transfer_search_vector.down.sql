@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transfers_search_vector;
+ALTER TABLE transfers DROP COLUMN IF EXISTS search_vector;
+
+CREATE INDEX IF NOT EXISTS idx_transfers_text ON transfers
+    USING GIN (to_tsvector('simple', COALESCE(memo, '') || ' ' || COALESCE(reference, '')));
@@ -0,0 +1,7 @@
+-- Поисковый документ перевода хранится в колонке: запрос сравнивает с ней напрямую
+-- и поэтому использует GIN-индекс. Добавление колонки перезаписывает таблицу.
+ALTER TABLE transfers ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(memo, '') || ' ' || COALESCE(reference, ''))) STORED;
+
+DROP INDEX IF EXISTS idx_transfers_text;
+CREATE INDEX idx_transfers_search_vector ON transfers USING GIN (search_vector);
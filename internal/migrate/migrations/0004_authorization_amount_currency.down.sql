@@ -0,0 +1,3 @@
+UPDATE authorizations
+SET captured_amount = ROUND(amount_rub * captured_amount / amount, 2), amount = amount_rub, currency = 'RUB';
+ALTER TABLE authorizations DROP COLUMN IF EXISTS amount_rub;
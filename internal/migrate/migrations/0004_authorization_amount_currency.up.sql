@@ -0,0 +1,5 @@
+-- Сумма авторизации хранится в ее валюте, а удержание — в рублях, как у сессий оплаты.
+-- Прежние авторизации хранили сумму в рублях, поэтому их валюта становится RUB.
+ALTER TABLE authorizations ADD COLUMN amount_rub DECIMAL(15, 2);
+UPDATE authorizations SET amount_rub = amount, currency = 'RUB';
+ALTER TABLE authorizations ALTER COLUMN amount_rub SET NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transfer_batches_queue;
+ALTER TABLE transfer_batches DROP COLUMN IF EXISTS error;
+ALTER TABLE transfer_batches DROP COLUMN IF EXISTS locked_until;
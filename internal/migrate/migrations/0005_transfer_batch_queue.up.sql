@@ -0,0 +1,9 @@
+-- Пакеты переводов обрабатывает фоновый обработчик: он забирает пакет на время аренды,
+-- а после перезапуска подхватывает необработанные и прерванные пакеты.
+-- IF NOT EXISTS: в части баз эти изменения уже применены под версией 2.
+ALTER TABLE transfer_batches ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+-- Ошибка уровня всего пакета, не относящаяся к конкретной строке
+ALTER TABLE transfer_batches ADD COLUMN IF NOT EXISTS error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_transfer_batches_queue ON transfer_batches (created_at)
+    WHERE status IN ('pending', 'processing');
@@ -0,0 +1,2 @@
+UPDATE escrow_transfers SET amount = amount_rub, currency = 'RUB';
+ALTER TABLE escrow_transfers DROP COLUMN IF EXISTS amount_rub;
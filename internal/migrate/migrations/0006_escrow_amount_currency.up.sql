@@ -0,0 +1,5 @@
+-- Сумма сделки хранится в ее валюте, а удержание и перевод получателю — в рублях, как у авторизаций.
+-- Прежние сделки хранили сумму в рублях, поэтому их валюта становится RUB.
+ALTER TABLE escrow_transfers ADD COLUMN amount_rub DECIMAL(15, 2);
+UPDATE escrow_transfers SET amount_rub = amount, currency = 'RUB';
+ALTER TABLE escrow_transfers ALTER COLUMN amount_rub SET NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE transfers DROP COLUMN IF EXISTS counts_toward_limit;
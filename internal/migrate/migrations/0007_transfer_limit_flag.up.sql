@@ -0,0 +1,3 @@
+-- Зачисления по уже удержанным или уже полученным средствам (захват авторизации, выпуск
+-- сделки, возврат мерчанта, перенос остатка при закрытии) не должны расходовать дневной лимит
+ALTER TABLE transfers ADD COLUMN counts_toward_limit BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,3 @@
+ALTER TABLE escrow_transfers DROP COLUMN IF EXISTS release_error;
+ALTER TABLE escrow_transfers DROP COLUMN IF EXISTS next_release_at;
+ALTER TABLE escrow_transfers DROP COLUMN IF EXISTS release_attempts;
@@ -0,0 +1,5 @@
+-- Сделку, которую не удалось выпустить по дедлайну (счет заморожен или закрыт), откладываем
+-- с нарастающей паузой, чтобы она не занимала очередь выпуска
+ALTER TABLE escrow_transfers ADD COLUMN release_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE escrow_transfers ADD COLUMN next_release_at TIMESTAMPTZ;
+ALTER TABLE escrow_transfers ADD COLUMN release_error TEXT;
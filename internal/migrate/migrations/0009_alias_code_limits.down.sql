@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS alias_code_sends;
+ALTER TABLE aliases DROP COLUMN IF EXISTS locked_until;
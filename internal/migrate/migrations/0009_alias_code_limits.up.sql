@@ -0,0 +1,16 @@
+-- Коды подтверждения псевдонимов: журнал отправок для ограничения частоты и блокировка
+-- псевдонима после исчерпания попыток ввода. Счетчик попыток больше не сбрасывается
+-- повторной отправкой, поэтому прежние значения обнуляем.
+ALTER TABLE aliases ADD COLUMN locked_until TIMESTAMPTZ;
+UPDATE aliases SET attempts = 0 WHERE NOT verified;
+
+CREATE TABLE alias_code_sends (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    value VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_alias_code_sends_value ON alias_code_sends (type, value, created_at);
+CREATE INDEX idx_alias_code_sends_user ON alias_code_sends (user_id, created_at);
@@ -0,0 +1,3 @@
+-- Перевод сохраненному получателю обходится без повторного ввода пароля, только если
+-- получатель был добавлен с паролем. Прежние записи добавлялись без него.
+ALTER TABLE beneficiaries ADD COLUMN password_confirmed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AccountStatusActive        = "active"
+	AccountStatusFrozen        = "frozen"
+	AccountStatusDebitBlocked  = "debit_blocked"
+	AccountStatusCreditBlocked = "credit_blocked"
+	AccountStatusClosed        = "closed"
+)
+
+type AccountStatusChange struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	AccountID uuid.UUID `json:"account_id" db:"account_id"`
+	OldStatus string    `json:"old_status" db:"old_status"`
+	NewStatus string    `json:"new_status" db:"new_status"`
+	ChangedBy uuid.UUID `json:"changed_by" db:"changed_by"`
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type SetAccountStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active frozen debit_blocked credit_blocked closed"`
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type FreezeAccountRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// Ненулевой остаток при закрытии переводится на счет SweepToEmail
+type CloseAccountRequest struct {
+	Reason       string `json:"reason" validate:"required,max=255"`
+	SweepToEmail string `json:"sweep_to_email" validate:"omitempty,email"`
+	Password     string `json:"password"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AliasEmail         = "email"
+	AliasPhone         = "phone"
+	AliasUsername      = "username"
+	AliasAccountNumber = "account_number"
+)
+
+// Recipient — получатель перевода в виде псевдонима из справочника
+type Recipient struct {
+	Type  string `json:"type" validate:"required"`
+	Value string `json:"value" validate:"required"`
+}
+
+type Alias struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Type       string     `json:"type" db:"type"`
+	Value      string     `json:"value" db:"value"`
+	Verified   bool       `json:"verified" db:"verified"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+}
+
+type CreateAliasRequest struct {
+	Type  string `json:"type" validate:"required,oneof=phone username"`
+	Value string `json:"value" validate:"required"`
+}
+
+type VerifyAliasRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
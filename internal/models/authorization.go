@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HoldReasonAuthorization = "authorization"
+	HoldStatusExpired       = "expired"
+
+	AuthorizationStatusAuthorized = "authorized"
+	AuthorizationStatusCaptured   = "captured"
+	AuthorizationStatusVoided     = "voided"
+	AuthorizationStatusExpired    = "expired"
+)
+
+// Authorization — двухфазное списание: плательщик резервирует сумму в пользу получателя,
+// получатель списывает ее полностью или частично, либо отменяет
+type Authorization struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	HoldID         uuid.UUID `json:"hold_id" db:"hold_id"`
+	PayerAccountID uuid.UUID `json:"payer_account_id" db:"payer_account_id"`
+	PayeeAccountID uuid.UUID `json:"payee_account_id" db:"payee_account_id"`
+	PayerEmail     string    `json:"payer_email" db:"payer_email"`
+	PayeeEmail     string    `json:"payee_email" db:"payee_email"`
+	// Amount и CapturedAmount — в валюте авторизации, удержание на счете — в AmountRUB
+	Amount         float64    `json:"amount" db:"amount"`
+	AmountRUB      float64    `json:"-" db:"amount_rub"`
+	CapturedAmount float64    `json:"captured_amount" db:"captured_amount"`
+	Currency       string     `json:"currency" db:"currency"`
+	Memo           string     `json:"memo,omitempty" db:"memo"`
+	Status         string     `json:"status" db:"status"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	TransferID     *uuid.UUID `json:"transfer_id,omitempty" db:"transfer_id"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type AuthorizeRequest struct {
+	PayeeEmail string    `json:"payee_email" validate:"required,email"`
+	Amount     float64   `json:"amount" validate:"gt=0"`
+	Currency   string    `json:"currency" validate:"omitempty,currency"`
+	Memo       string    `json:"memo" validate:"max=500"`
+	ExpiresAt  time.Time `json:"expires_at"` // По умолчанию — через 7 дней
+	Password   string    `json:"password"`
+}
+
+// Если Amount не указан, списывается вся зарезервированная сумма
+type CaptureRequest struct {
+	Amount float64 `json:"amount" validate:"gte=0"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+
+	BatchStatusPending            = "pending"
+	BatchStatusProcessing         = "processing"
+	BatchStatusCompleted          = "completed"
+	BatchStatusPartiallyCompleted = "partially_completed"
+	BatchStatusFailed             = "failed"
+
+	BatchItemPending   = "pending"
+	BatchItemCompleted = "completed"
+	BatchItemFailed    = "failed"
+	BatchItemSkipped   = "skipped"
+)
+
+type TransferBatch struct {
+	ID          uuid.UUID           `json:"id" db:"id"`
+	UserID      uuid.UUID           `json:"user_id" db:"user_id"`
+	AccountID   uuid.UUID           `json:"account_id" db:"account_id"`
+	Mode        string              `json:"mode" db:"mode"`
+	Status      string              `json:"status" db:"status"`
+	TotalLines  int                 `json:"total_lines" db:"total_lines"`
+	Succeeded   int                 `json:"succeeded" db:"succeeded"`
+	Failed      int                 `json:"failed" db:"failed"`
+	CreatedAt   time.Time           `json:"created_at" db:"created_at"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty" db:"completed_at"`
+	Error       string              `json:"error,omitempty" db:"error"`
+	Items       []TransferBatchItem `json:"items,omitempty"`
+}
+
+type TransferBatchItem struct {
+	LineNo      int        `json:"line"`
+	ToEmail     string     `json:"to_email" db:"to_email"`
+	ToAccountID uuid.UUID  `json:"-" db:"to_account_id"`
+	Amount      float64    `json:"amount" db:"amount"`
+	Currency    string     `json:"currency" db:"currency"`
+	Memo        string     `json:"memo,omitempty" db:"memo"`
+	Reference   string     `json:"reference,omitempty" db:"reference"`
+	Status      string     `json:"status" db:"status"`
+	TransferID  *uuid.UUID `json:"transfer_id,omitempty" db:"transfer_id"`
+	Error       string     `json:"error,omitempty" db:"error"`
+}
+
+type BatchTransferLine struct {
+	ToEmail  string  `json:"to_email" validate:"required,email"`
+	Amount   float64 `json:"amount" validate:"gt=0"`
+	Currency string  `json:"currency" validate:"omitempty,currency"`
+	TransferDetails
+}
+
+type BatchTransferRequest struct {
+	Mode     string              `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Password string              `json:"password"`
+	Items    []BatchTransferLine `json:"items" validate:"required,min=1,dive"`
+}
+
+type BatchLineError struct {
+	LineNo int    `json:"line"`
+	Error  string `json:"error"`
+}
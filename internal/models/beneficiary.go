@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Beneficiary struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	AccountID  uuid.UUID `json:"account_id" db:"account_id"`
+	Email      string    `json:"email" db:"email"`
+	Nickname   string    `json:"nickname" db:"nickname"`
+	IsFavorite bool      `json:"is_favorite" db:"is_favorite"`
+	// PasswordConfirmed — получатель добавлен с вводом пароля, переводы ему не требуют step-up
+	PasswordConfirmed bool      `json:"-" db:"password_confirmed"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateBeneficiaryRequest struct {
+	Email      string `json:"email" validate:"required,email"`
+	Nickname   string `json:"nickname" validate:"required,max=100"`
+	IsFavorite bool   `json:"is_favorite"`
+	Password   string `json:"password"`
+}
+
+// Поля, не переданные в запросе, остаются без изменений
+type UpdateBeneficiaryRequest struct {
+	Nickname   *string `json:"nickname" validate:"omitempty,max=100"`
+	IsFavorite *bool   `json:"is_favorite"`
+}
+
+// Подтверждение получателя перед переводом
+type RecipientConfirmation struct {
+	Type           string     `json:"type"`
+	Value          string     `json:"value"`
+	MaskedName     string     `json:"masked_name"`
+	BeneficiaryID  *uuid.UUID `json:"beneficiary_id,omitempty"`
+	StepUpRequired bool       `json:"step_up_required"`
+}
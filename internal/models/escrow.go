@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HoldStatusActive   = "active"
+	HoldStatusReleased = "released"
+	HoldStatusCaptured = "captured"
+
+	HoldReasonEscrow = "escrow"
+
+	EscrowStatusHeld     = "held"
+	EscrowStatusDisputed = "disputed"
+	EscrowStatusReleased = "released"
+	EscrowStatusRefunded = "refunded"
+
+	EscrowResolutionConfirmed = "confirmed"
+	EscrowResolutionDeadline  = "deadline"
+	EscrowResolutionCancelled = "cancelled"
+	EscrowResolutionDisputed  = "disputed"
+
+	EscrowOutcomeRelease = "release"
+	EscrowOutcomeRefund  = "refund"
+)
+
+// Hold резервирует часть баланса счета: деньги остаются на счете, но недоступны для трат
+type Hold struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	AccountID uuid.UUID  `json:"account_id" db:"account_id"`
+	Amount    float64    `json:"amount" db:"amount"`
+	Status    string     `json:"status" db:"status"`
+	Reason    string     `json:"reason" db:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type Escrow struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	FromAccountID uuid.UUID `json:"from_account_id" db:"from_account_id"`
+	ToAccountID   uuid.UUID `json:"to_account_id" db:"to_account_id"`
+	FromEmail     string    `json:"from_email" db:"from_email"`
+	ToEmail       string    `json:"to_email" db:"to_email"`
+	HoldID        uuid.UUID `json:"hold_id" db:"hold_id"`
+	// Amount — в валюте сделки, удержание и перевод получателю — в AmountRUB
+	Amount             float64    `json:"amount" db:"amount"`
+	AmountRUB          float64    `json:"-" db:"amount_rub"`
+	Currency           string     `json:"currency" db:"currency"`
+	Memo               string     `json:"memo,omitempty" db:"memo"`
+	Status             string     `json:"status" db:"status"`
+	Resolution         string     `json:"resolution,omitempty" db:"resolution"`
+	SenderConfirmed    bool       `json:"sender_confirmed" db:"sender_confirmed"`
+	RecipientConfirmed bool       `json:"recipient_confirmed" db:"recipient_confirmed"`
+	Deadline           time.Time  `json:"deadline" db:"deadline"`
+	TransferID         *uuid.UUID `json:"transfer_id,omitempty" db:"transfer_id"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	ResolvedAt         *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+type CreateEscrowRequest struct {
+	ToEmail  string    `json:"to_email" validate:"required,email"`
+	Amount   float64   `json:"amount" validate:"gt=0"`
+	Currency string    `json:"currency" validate:"omitempty,currency"`
+	Memo     string    `json:"memo" validate:"max=500"`
+	Deadline time.Time `json:"deadline" validate:"required"`
+	Password string    `json:"password"`
+}
+
+// ResolveEscrowRequest — решение администратора по спорной сделке
+type ResolveEscrowRequest struct {
+	Outcome string `json:"outcome" validate:"required,oneof=release refund"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AggregateTransfer = "transfer"
+	AggregateAccount  = "account"
+	AggregateUser     = "user"
+
+	EventAccountStatusChanged = "account.status_changed"
+	EventNewDeviceLogin       = "auth.new_device_login"
+	EventLimitExceeded        = "limit.exceeded"
+)
+
+// DomainEvent — событие из outbox. Sequence монотонно растет и служит смещением потребителя.
+type DomainEvent struct {
+	Sequence      int64           `json:"sequence" db:"id"`
+	ID            uuid.UUID       `json:"id" db:"event_id"`
+	Type          string          `json:"type" db:"event_type"`
+	AggregateType string          `json:"aggregate_type" db:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id" db:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
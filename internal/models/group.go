@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SplitEqual      = "equal"
+	SplitPercentage = "percentage"
+	SplitExact      = "exact"
+)
+
+type ExpenseGroup struct {
+	ID        uuid.UUID     `json:"id" db:"id"`
+	Name      string        `json:"name" db:"name"`
+	CreatedBy uuid.UUID     `json:"created_by" db:"created_by"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	Members   []GroupMember `json:"members,omitempty"`
+}
+
+type GroupMember struct {
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	Email    string    `json:"email" db:"email"`
+	FullName string    `json:"full_name" db:"full_name"`
+}
+
+type Expense struct {
+	ID          uuid.UUID      `json:"id" db:"id"`
+	GroupID     uuid.UUID      `json:"group_id" db:"group_id"`
+	PaidBy      uuid.UUID      `json:"paid_by" db:"paid_by"`
+	Amount      float64        `json:"amount" db:"amount"`
+	Description string         `json:"description" db:"description"`
+	SplitType   string         `json:"split_type" db:"split_type"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	Shares      []ExpenseShare `json:"shares"`
+}
+
+type ExpenseShare struct {
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	Amount float64   `json:"amount" db:"amount"`
+}
+
+type CreateGroupRequest struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	MemberEmails []string `json:"member_emails" validate:"dive,email"`
+}
+
+type AddGroupMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Для split_type=equal участники необязательны (по умолчанию все члены группы),
+// для percentage заполняется Percent, для exact — Amount
+type CreateExpenseRequest struct {
+	Description  string               `json:"description" validate:"required,max=255"`
+	Amount       float64              `json:"amount" validate:"gt=0"`
+	SplitType    string               `json:"split_type" validate:"required,oneof=equal percentage exact"`
+	Participants []ExpenseParticipant `json:"participants" validate:"dive"`
+}
+
+type ExpenseParticipant struct {
+	UserID  uuid.UUID `json:"user_id" validate:"required"`
+	Percent float64   `json:"percent,omitempty"`
+	Amount  float64   `json:"amount,omitempty"`
+}
+
+// Положительный Net — участнику должны, отрицательный — должен он
+type GroupBalance struct {
+	GroupMember
+	Net float64 `json:"net"`
+}
+
+type GroupDebt struct {
+	From   uuid.UUID `json:"from_user_id"`
+	To     uuid.UUID `json:"to_user_id"`
+	Amount float64   `json:"amount"`
+}
+
+type GroupBalances struct {
+	Balances []GroupBalance `json:"balances"`
+	Debts    []GroupDebt    `json:"debts"`
+}
+
+type GroupSettlement struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	GroupID    uuid.UUID `json:"group_id" db:"group_id"`
+	From       uuid.UUID `json:"from_user_id" db:"from_user_id"`
+	To         uuid.UUID `json:"to_user_id" db:"to_user_id"`
+	Amount     float64   `json:"amount" db:"amount"`
+	TransferID uuid.UUID `json:"transfer_id" db:"transfer_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+type SettleGroupRequest struct {
+	Password string `json:"password"`
+}
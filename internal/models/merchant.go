@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CheckoutStatusOpen      = "open"
+	CheckoutStatusPaid      = "paid"
+	CheckoutStatusCompleted = "completed"
+	CheckoutStatusCancelled = "cancelled"
+	CheckoutStatusExpired   = "expired"
+	CheckoutStatusRefunded  = "refunded"
+
+	EventCheckoutPaid      = "checkout.session.paid"
+	EventCheckoutCompleted = "checkout.session.completed"
+	EventCheckoutCancelled = "checkout.session.cancelled"
+	EventCheckoutRefunded  = "checkout.session.refunded"
+)
+
+// CheckoutEvents — события, на которые подписан вебхук мерчанта
+var CheckoutEvents = []string{EventCheckoutPaid, EventCheckoutCompleted, EventCheckoutCancelled, EventCheckoutRefunded}
+
+// Merchant — профиль компании, принимающей платежи на счет пользователя-владельца
+type Merchant struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	UserID            uuid.UUID  `json:"user_id" db:"user_id"`
+	AccountID         uuid.UUID  `json:"account_id" db:"account_id"`
+	Name              string     `json:"name" db:"name"`
+	WebhookEndpointID *uuid.UUID `json:"webhook_endpoint_id,omitempty" db:"webhook_endpoint_id"`
+	WebhookURL        string     `json:"webhook_url,omitempty" db:"webhook_url"`
+	WebhookSecret     string     `json:"webhook_secret,omitempty" db:"webhook_secret"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+}
+
+type MerchantRequest struct {
+	Name       string `json:"name" validate:"required,max=100"`
+	WebhookURL string `json:"webhook_url" validate:"omitempty,url"`
+}
+
+type CheckoutSession struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	MerchantID     uuid.UUID  `json:"merchant_id" db:"merchant_id"`
+	MerchantName   string     `json:"merchant_name" db:"merchant_name"`
+	Amount         float64    `json:"amount" db:"amount"`
+	Currency       string     `json:"currency" db:"currency"`
+	Description    string     `json:"description,omitempty" db:"description"`
+	Reference      string     `json:"reference,omitempty" db:"reference"`
+	Status         string     `json:"status" db:"status"`
+	SuccessURL     string     `json:"success_url" db:"success_url"`
+	CancelURL      string     `json:"cancel_url" db:"cancel_url"`
+	PayerAccountID *uuid.UUID `json:"payer_account_id,omitempty" db:"payer_account_id"`
+	TransferID     *uuid.UUID `json:"transfer_id,omitempty" db:"transfer_id"`
+	RefundedAmount float64    `json:"refunded_amount" db:"refunded_amount"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	PaidAt         *time.Time `json:"paid_at,omitempty" db:"paid_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CheckoutURL    string     `json:"checkout_url,omitempty" db:"-"`
+}
+
+type CreateCheckoutSessionRequest struct {
+	Amount      float64    `json:"amount" validate:"gt=0"`
+	Currency    string     `json:"currency" validate:"omitempty,currency"`
+	Description string     `json:"description" validate:"max=500"`
+	Reference   string     `json:"reference" validate:"max=100"`
+	SuccessURL  string     `json:"success_url" validate:"required,url"`
+	CancelURL   string     `json:"cancel_url" validate:"required,url"`
+	ExpiresAt   *time.Time `json:"expires_at"` // По умолчанию — через 24 часа
+}
+
+type PayCheckoutSessionRequest struct {
+	Password string `json:"password"`
+}
+
+// AbandonCheckoutSessionRequest — отказ плательщика; token берется из checkout_url
+type AbandonCheckoutSessionRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// CheckoutRedirect — куда вернуть плательщика после оплаты или отмены
+type CheckoutRedirect struct {
+	Session     *CheckoutSession `json:"session"`
+	RedirectURL string           `json:"redirect_url"`
+}
+
+type Refund struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	SessionID  uuid.UUID `json:"session_id" db:"session_id"`
+	Amount     float64   `json:"amount" db:"amount"`
+	Currency   string    `json:"currency" db:"currency"`
+	Reason     string    `json:"reason,omitempty" db:"reason"`
+	TransferID uuid.UUID `json:"transfer_id" db:"transfer_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+type RefundRequest struct {
+	Amount float64 `json:"amount" validate:"gte=0"` // 0 — вернуть весь остаток
+	Reason string  `json:"reason" validate:"max=255"`
+}
+
+// PublicCheckoutSession — то, что видит плательщик на странице оплаты
+type PublicCheckoutSession struct {
+	ID           uuid.UUID `json:"id"`
+	MerchantName string    `json:"merchant_name"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
+	Description  string    `json:"description,omitempty"`
+	Status       string    `json:"status"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
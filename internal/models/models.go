@@ -7,16 +7,40 @@ import (
 )
 
 type Account struct {
-	ID      uuid.UUID `json:"id" db:"id"`
-	UserID  uuid.UUID `json:"user_id" db:"user_id"` // Добавьте это поле
-	Balance float64   `json:"balance" db:"balance"`
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"` // Добавьте это поле
+	Number      string    `json:"account_number" db:"account_number"`
+	Balance     float64   `json:"balance" db:"balance"`
+	HeldBalance float64   `json:"held_balance" db:"held_balance"` // Зарезервировано удержаниями
+	Status      string    `json:"status" db:"status"`
+}
+
+// Доступный остаток: учетный баланс за вычетом удержаний
+func (a Account) AvailableBalance() float64 {
+	return a.Balance - a.HeldBalance
+}
+
+type Balance struct {
+	AccountID     uuid.UUID `json:"account_id"`
+	AccountNumber string    `json:"account_number"`
+	Ledger        float64   `json:"balance"`
+	Available     float64   `json:"available_balance"`
+	Held          float64   `json:"held_balance"`
+	Currency      string    `json:"currency"`
 }
 
 type TransferRequest struct {
 	From     string  `json:"from"`
 	To       string  `json:"to"`
-	Amount   float64 `json:"amount"`
-	Currency string  `json:"currency"` // Например: "USD", "EUR"
+	Amount   float64 `json:"amount" validate:"gt=0"`
+	Currency string  `json:"currency" validate:"required,currency"` // Например: "USD", "EUR"
+	TransferDetails
+}
+
+// Назначение платежа и референс видны обеим сторонам перевода
+type TransferDetails struct {
+	Memo      string `json:"memo,omitempty" validate:"max=500"`
+	Reference string `json:"reference,omitempty" validate:"max=100"`
 }
 
 type Transfer struct {
@@ -27,5 +51,12 @@ type Transfer struct {
 	ToEmail   string    `json:"to_email" db:"to_email"`
 	Amount    float64   `json:"amount" db:"amount"`
 	Currency  string    `json:"currency" db:"currency"`
+	Memo      string    `json:"memo" db:"memo"`
+	Reference string    `json:"reference" db:"reference"`
+	Tags      []string  `json:"tags"` // Личные теги владельца счета
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
+
+type SetTransferTagsRequest struct {
+	Tags []string `json:"tags" validate:"max=10,dive,min=1,max=50"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+	NotificationChannelPush  = "push"
+
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+	NotificationSkipped = "skipped"
+
+	LanguageRU = "ru"
+	LanguageEN = "en"
+
+	// Виды уведомлений. Перевод порождает два уведомления — отправителю и получателю.
+	NotificationTransferSent     = "transfer.sent"
+	NotificationTransferReceived = "transfer.received"
+	NotificationDeposit          = EventDepositCompleted
+	NotificationNewDeviceLogin   = EventNewDeviceLogin
+	NotificationLimitExceeded    = EventLimitExceeded
+)
+
+var (
+	NotificationChannels = []string{NotificationChannelEmail, NotificationChannelSMS, NotificationChannelPush}
+	NotificationKinds    = []string{
+		NotificationTransferSent, NotificationTransferReceived, NotificationDeposit,
+		NotificationNewDeviceLogin, NotificationLimitExceeded,
+	}
+	NotificationLanguages = []string{LanguageRU, LanguageEN}
+)
+
+// NotificationPreferences — язык уведомлений и каналы для каждого вида уведомлений.
+// Вид без каналов (пустой список) отключен.
+type NotificationPreferences struct {
+	Language string              `json:"language" db:"language" validate:"omitempty,oneof=ru en"`
+	Channels map[string][]string `json:"channels" db:"channels"`
+}
+
+// Notification — запись о доставке уведомления по одному каналу
+type Notification struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	EventID   uuid.UUID  `json:"event_id" db:"event_id"`
+	Kind      string     `json:"kind" db:"kind"`
+	Channel   string     `json:"channel" db:"channel"`
+	Recipient string     `json:"recipient" db:"recipient"`
+	Subject   string     `json:"subject" db:"subject"`
+	Body      string     `json:"body" db:"body"`
+	Status    string     `json:"status" db:"status"`
+	Error     string     `json:"error,omitempty" db:"error"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+}
+
+// NewDeviceLoginEventData — вход с устройства, которого раньше не было у пользователя
+type NewDeviceLoginEventData struct {
+	UserID    uuid.UUID `json:"user_id"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LimitExceededEventData — отклоненный перевод сверх дневного лимита, суммы в RUB
+type LimitExceededEventData struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Amount    float64   `json:"amount"`
+	Used      float64   `json:"used"`
+	Limit     float64   `json:"limit"`
+	CreatedAt time.Time `json:"created_at"`
+}
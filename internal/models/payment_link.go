@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PaymentLinkStatusActive    = "active"
+	PaymentLinkStatusUsed      = "used"
+	PaymentLinkStatusCancelled = "cancelled"
+	PaymentLinkStatusExpired   = "expired"
+)
+
+// PaymentLink — ссылка (или QR-код) для получения денег. Без суммы плательщик вводит ее сам.
+type PaymentLink struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	Code          string     `json:"code" db:"code"`
+	AccountID     uuid.UUID  `json:"account_id" db:"account_id"`
+	Amount        *float64   `json:"amount,omitempty" db:"amount"`
+	Currency      string     `json:"currency" db:"currency"`
+	Memo          string     `json:"memo,omitempty" db:"memo"`
+	SingleUse     bool       `json:"single_use" db:"single_use"`
+	Status        string     `json:"status" db:"status"`
+	PaymentsCount int        `json:"payments_count" db:"payments_count"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	URL           string     `json:"url,omitempty" db:"-"`
+}
+
+// PublicPaymentLink — то, что видит любой, у кого есть ссылка
+type PublicPaymentLink struct {
+	Code          string     `json:"code"`
+	RecipientName string     `json:"recipient_name"`
+	Amount        *float64   `json:"amount,omitempty"`
+	Currency      string     `json:"currency"`
+	Memo          string     `json:"memo,omitempty"`
+	Status        string     `json:"status"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+}
+
+type CreatePaymentLinkRequest struct {
+	Amount    *float64   `json:"amount" validate:"omitempty,gt=0"`
+	Currency  string     `json:"currency" validate:"omitempty,currency"`
+	Memo      string     `json:"memo" validate:"max=500"`
+	SingleUse bool       `json:"single_use"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type PayPaymentLinkRequest struct {
+	Amount   float64 `json:"amount" validate:"omitempty,gt=0"`
+	Password string  `json:"password"`
+}
+
+// PaymentLinkPayment — один платеж по ссылке
+type PaymentLinkPayment struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	LinkID         uuid.UUID `json:"link_id" db:"link_id"`
+	PayerAccountID uuid.UUID `json:"payer_account_id" db:"payer_account_id"`
+	TransferID     uuid.UUID `json:"transfer_id" db:"transfer_id"`
+	Amount         float64   `json:"amount" db:"amount"`
+	Currency       string    `json:"currency" db:"currency"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
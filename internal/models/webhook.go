@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+
+	EventTransferCompleted = "transfer.completed"
+	EventTransferReceived  = "transfer.received"
+	EventDepositCompleted  = "deposit.completed"
+	EventAccountFrozen     = "account.frozen"
+	EventAccountUnfrozen   = "account.unfrozen"
+	EventAccountClosed     = "account.closed"
+
+	// EventWebhookTest отправляется только вручную, для проверки эндпоинта
+	EventWebhookTest = "webhook.test"
+)
+
+// WebhookEvents — типы событий, на которые можно подписаться
+var WebhookEvents = append([]string{
+	EventTransferCompleted, EventTransferReceived, EventDepositCompleted,
+	EventAccountFrozen, EventAccountUnfrozen, EventAccountClosed,
+}, CheckoutEvents...)
+
+// WebhookEndpoint — адрес, на который отправляются подписанные события
+type WebhookEndpoint struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	URL       string    `json:"url" db:"url"`
+	Secret    string    `json:"secret,omitempty" db:"secret"`
+	Events    []string  `json:"events" db:"events"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookEvent — тело запроса, которое получает подписчик
+type WebhookEvent struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	EndpointID     uuid.UUID       `json:"endpoint_id" db:"endpoint_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	RedeliveryOf   *uuid.UUID      `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      string          `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+type CreateWebhookEndpointRequest struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events" validate:"required,min=1,dive,required"`
+}
+
+// Незаданные поля не меняются
+type UpdateWebhookEndpointRequest struct {
+	URL    *string  `json:"url" validate:"omitempty,url"`
+	Events []string `json:"events" validate:"omitempty,min=1,dive,required"`
+	Active *bool    `json:"active"`
+}
+
+type TransferEventData struct {
+	TransferID    uuid.UUID `json:"transfer_id"`
+	FromAccountID uuid.UUID `json:"from_account_id"`
+	ToAccountID   uuid.UUID `json:"to_account_id"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Memo          string    `json:"memo,omitempty"`
+	Reference     string    `json:"reference,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type DepositEventData struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Amount    float64   `json:"amount"`
+	Balance   float64   `json:"balance"`
+}
+
+type AccountStatusEventData struct {
+	AccountID uuid.UUID `json:"account_id"`
+	OldStatus string    `json:"old_status"`
+	NewStatus string    `json:"new_status"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// WebhookDispatch — доставка вместе с адресом и секретом эндпоинта
+type WebhookDispatch struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+}
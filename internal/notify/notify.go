@@ -0,0 +1,194 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"money-transfer-service/internal/config"
+	"money-transfer-service/internal/models"
+)
+
+// Message — готовое к отправке уведомление. To — адрес в терминах канала:
+// email, номер телефона или идентификатор пользователя для push.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier доставляет уведомления по одному каналу
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New собирает каналы доставки. Адрес SMTP включает отправку писем через SMTP,
+// адрес SMS-шлюза — отправку SMS, File — запись остальных уведомлений в файл;
+// без них все пишется в лог.
+func New(cfg config.NotifyConfig) map[string]Notifier {
+	var fallback func(channel string) Notifier
+	if cfg.File != "" {
+		file := NewFileNotifier(cfg.File)
+		fallback = func(channel string) Notifier { return file.Channel(channel) }
+	} else {
+		fallback = func(channel string) Notifier { return LogNotifier{Channel: channel} }
+	}
+
+	notifiers := make(map[string]Notifier, len(models.NotificationChannels))
+	for _, channel := range models.NotificationChannels {
+		notifiers[channel] = fallback(channel)
+	}
+	if cfg.SMTPAddr != "" {
+		notifiers[models.NotificationChannelEmail] = &SMTPNotifier{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.SMTPFrom,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword.Value(),
+		}
+	}
+	if cfg.SMSGatewayURL != "" {
+		notifiers[models.NotificationChannelSMS] = NewSMSGatewayNotifier(cfg.SMSGatewayURL, cfg.SMSGatewayToken.Value())
+	}
+	return notifiers
+}
+
+// LogNotifier пишет уведомления в лог вместо отправки. Только для локальной разработки.
+type LogNotifier struct {
+	Channel string
+}
+
+func (n LogNotifier) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "notification", "channel", n.Channel, "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// FileNotifier дописывает уведомления в файл по одному JSON-объекту на строку
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+// Channel возвращает Notifier, помечающий записи указанным каналом
+func (f *FileNotifier) Channel(channel string) Notifier {
+	return fileChannel{file: f, channel: channel}
+}
+
+type fileChannel struct {
+	file    *FileNotifier
+	channel string
+}
+
+func (c fileChannel) Send(ctx context.Context, msg Message) error {
+	return c.file.write(c.channel, msg)
+}
+
+func (f *FileNotifier) write(channel string, msg Message) error {
+	line, err := json.Marshal(struct {
+		Time    time.Time `json:"time"`
+		Channel string    `json:"channel"`
+		To      string    `json:"to"`
+		Subject string    `json:"subject"`
+		Body    string    `json:"body"`
+	}{time.Now(), channel, msg.To, msg.Subject, msg.Body})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// SMTPNotifier отправляет письма через SMTP-сервер. Авторизация используется, только если
+// задан Username.
+type SMTPNotifier struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, err := net.SplitHostPort(n.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP address: %w", err)
+		}
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return smtp.SendMail(n.Addr, auth, n.From, []string{msg.To}, []byte(b.String()))
+}
+
+// SMSGatewayNotifier отправляет SMS через HTTP-шлюз провайдера:
+// POST {"to": "+79991234567", "text": "..."} с токеном в Authorization
+type SMSGatewayNotifier struct {
+	URL    string
+	Token  string
+	client *http.Client
+}
+
+func NewSMSGatewayNotifier(url, token string) *SMSGatewayNotifier {
+	return &SMSGatewayNotifier{URL: url, Token: token, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *SMSGatewayNotifier) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(map[string]string{"to": msg.To, "text": msg.Body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms gateway: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"money-transfer-service/internal/models"
+)
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var funcs = template.FuncMap{
+	"money": func(amount float64) string { return fmt.Sprintf("%.2f", amount) },
+}
+
+func mustTemplate(subject, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New("subject").Funcs(funcs).Parse(subject)),
+		body:    template.Must(template.New("body").Funcs(funcs).Parse(body)),
+	}
+}
+
+// templates — тексты уведомлений по виду и языку. Данные шаблона собирает сервис уведомлений.
+var templates = map[string]map[string]messageTemplate{
+	models.NotificationTransferSent: {
+		models.LanguageRU: mustTemplate(
+			"Перевод {{money .Amount}} {{.Currency}} выполнен",
+			"Вы перевели {{money .Amount}} {{.Currency}} получателю {{.Counterparty}}.{{if .Memo}}\nНазначение: {{.Memo}}{{end}}\nДоступный остаток: {{money .Balance}} RUB."),
+		models.LanguageEN: mustTemplate(
+			"Transfer of {{money .Amount}} {{.Currency}} completed",
+			"You sent {{money .Amount}} {{.Currency}} to {{.Counterparty}}.{{if .Memo}}\nMemo: {{.Memo}}{{end}}\nAvailable balance: {{money .Balance}} RUB."),
+	},
+	models.NotificationTransferReceived: {
+		models.LanguageRU: mustTemplate(
+			"Поступил перевод {{money .Amount}} {{.Currency}}",
+			"Вам поступил перевод {{money .Amount}} {{.Currency}} от {{.Counterparty}}.{{if .Memo}}\nНазначение: {{.Memo}}{{end}}\nДоступный остаток: {{money .Balance}} RUB."),
+		models.LanguageEN: mustTemplate(
+			"You received {{money .Amount}} {{.Currency}}",
+			"You received {{money .Amount}} {{.Currency}} from {{.Counterparty}}.{{if .Memo}}\nMemo: {{.Memo}}{{end}}\nAvailable balance: {{money .Balance}} RUB."),
+	},
+	models.NotificationDeposit: {
+		models.LanguageRU: mustTemplate(
+			"Счет пополнен на {{money .Amount}} RUB",
+			"Ваш счет пополнен на {{money .Amount}} RUB.\nБаланс: {{money .Balance}} RUB."),
+		models.LanguageEN: mustTemplate(
+			"Deposit of {{money .Amount}} RUB",
+			"{{money .Amount}} RUB has been deposited to your account.\nBalance: {{money .Balance}} RUB."),
+	},
+	models.NotificationNewDeviceLogin: {
+		models.LanguageRU: mustTemplate(
+			"Вход с нового устройства",
+			"В ваш аккаунт выполнен вход с нового устройства.\nУстройство: {{.UserAgent}}\nIP-адрес: {{.IP}}\nВремя: {{.Time.Format \"02.01.2006 15:04 MST\"}}\nЕсли это были не вы, смените пароль."),
+		models.LanguageEN: mustTemplate(
+			"New device sign-in",
+			"Your account was signed in from a new device.\nDevice: {{.UserAgent}}\nIP address: {{.IP}}\nTime: {{.Time.Format \"Jan 2, 2006 15:04 MST\"}}\nIf this wasn't you, change your password."),
+	},
+	models.NotificationLimitExceeded: {
+		models.LanguageRU: mustTemplate(
+			"Превышен дневной лимит переводов",
+			"Перевод на {{money .Amount}} RUB отклонен: превышен дневной лимит {{money .Limit}} RUB.\nСегодня уже переведено {{money .Used}} RUB."),
+		models.LanguageEN: mustTemplate(
+			"Daily transfer limit exceeded",
+			"A transfer of {{money .Amount}} RUB was declined: it exceeds your daily limit of {{money .Limit}} RUB.\nAlready sent today: {{money .Used}} RUB."),
+	},
+}
+
+// Render подставляет данные в шаблон вида kind на языке lang. Для неизвестного языка
+// используется русский.
+func Render(kind, lang string, data interface{}) (Message, error) {
+	byLang, ok := templates[kind]
+	if !ok {
+		return Message{}, fmt.Errorf("no template for notification %s", kind)
+	}
+	t, ok := byLang[lang]
+	if !ok {
+		t = byLang[models.LanguageRU]
+	}
+
+	var subject, body strings.Builder
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return Message{}, err
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return Message{}, err
+	}
+	return Message{Subject: subject.String(), Body: body.String()}, nil
+}
@@ -0,0 +1,126 @@
+// Package openapi отдает спецификацию OpenAPI 3 (openapi.json ведется вручную) и Swagger UI,
+// а также сверяет спецификацию с маршрутами роутера.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/swaggest/swgui/v5emb"
+)
+
+const (
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs/"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write(spec)
+	})
+}
+
+// UI — Swagger UI со встроенными ресурсами, без обращений к CDN
+func UI() http.Handler {
+	return v5emb.New("Money Transfer Service API", SpecPath, DocsPath)
+}
+
+// undocumented — служебные маршруты, которых не должно быть в спецификации
+var undocumented = map[string]bool{
+	"/":            true,
+	"/static/*":    true,
+	"/docs":        true,
+	DocsPath + "*": true,
+}
+
+// allMethods — столько методов chi регистрирует для r.Handle
+var allMethods = []string{
+	http.MethodConnect, http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions,
+	http.MethodPatch, http.MethodPost, http.MethodPut, http.MethodTrace,
+}
+
+// Check сверяет спецификацию с роутером: каждый маршрут должен быть описан,
+// и каждая операция спецификации — зарегистрирована. Вызывается из тестов роутера.
+func Check(routes chi.Routes) error {
+	documented, err := operations()
+	if err != nil {
+		return err
+	}
+
+	registered := map[string]map[string]bool{}
+	err = chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.Replace(route, "/*/", "/", -1)
+		if undocumented[route] {
+			return nil
+		}
+		if registered[route] == nil {
+			registered[route] = map[string]bool{}
+		}
+		registered[route][strings.ToLower(method)] = true
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("walk routes: %w", err)
+	}
+
+	var problems []string
+	for route, methods := range registered {
+		// r.Handle отвечает на любой метод: достаточно описать хотя бы один
+		if len(methods) == len(allMethods) {
+			if len(documented[route]) == 0 {
+				problems = append(problems, "undocumented route: "+route)
+			}
+			continue
+		}
+		for method := range methods {
+			if !documented[route][method] {
+				problems = append(problems, fmt.Sprintf("undocumented route: %s %s", strings.ToUpper(method), route))
+			}
+		}
+	}
+	for route, methods := range documented {
+		for method := range methods {
+			if !registered[route][method] {
+				problems = append(problems, fmt.Sprintf("documented but not registered: %s %s", strings.ToUpper(method), route))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("openapi spec does not match router:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// operations возвращает путь -> методы из спецификации
+func operations() (map[string]map[string]bool, error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi spec: %w", err)
+	}
+
+	ops := make(map[string]map[string]bool, len(doc.Paths))
+	for path, item := range doc.Paths {
+		ops[path] = map[string]bool{}
+		for method := range item {
+			switch method {
+			case "parameters", "summary", "description", "servers":
+				continue
+			}
+			ops[path][method] = true
+		}
+	}
+	return ops, nil
+}
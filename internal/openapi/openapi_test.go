@@ -0,0 +1,35 @@
+package openapi_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+
+	"money-transfer-service/internal/handler"
+	"money-transfer-service/internal/openapi"
+)
+
+// router собирает роутер так же, как cmd/server. Зависимости обработчикам не нужны:
+// запросы не выполняются, проверяется только таблица маршрутов.
+func router() chi.Router {
+	h := handler.NewHandler(nil, "")
+	return h.Routes(handler.NewAuthHandler(nil), handler.NewHealthHandler(nil, nil, nil), nil, "static")
+}
+
+func TestSpecMatchesRouter(t *testing.T) {
+	if err := openapi.Check(router()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckReportsUndocumentedRoute(t *testing.T) {
+	r := router()
+	r.Get("/api/undocumented", func(http.ResponseWriter, *http.Request) {})
+
+	err := openapi.Check(r)
+	if err == nil || !strings.Contains(err.Error(), "undocumented route: GET /api/undocumented") {
+		t.Fatalf("Check() = %v, want undocumented route error", err)
+	}
+}
@@ -0,0 +1,92 @@
+// Package problem формирует ответы об ошибках в формате RFC 7807 (application/problem+json).
+// Поле code — стабильный машиночитаемый код: по нему фронтенд выбирает локализованный текст,
+// detail остается подсказкой для разработчика и может меняться.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+// typePrefix — пространство имен для поля type; ссылки не обязаны открываться
+const typePrefix = "urn:money-transfer:problem:"
+
+// Общие коды для ошибок, у которых нет своего доменного кода
+const (
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeGone             = "gone"
+	CodeUnprocessable    = "unprocessable_entity"
+	CodeTooManyRequests  = "too_many_requests"
+	CodeInternal         = "internal_error"
+	CodeUnavailable      = "service_unavailable"
+)
+
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          CodeBadRequest,
+	http.StatusUnauthorized:        CodeUnauthorized,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusMethodNotAllowed:    CodeMethodNotAllowed,
+	http.StatusConflict:            CodeConflict,
+	http.StatusGone:                CodeGone,
+	http.StatusUnprocessableEntity: CodeUnprocessable,
+	http.StatusTooManyRequests:     CodeTooManyRequests,
+	http.StatusInternalServerError: CodeInternal,
+	http.StatusServiceUnavailable:  CodeUnavailable,
+}
+
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors — подробности по отдельным полям или строкам запроса
+	Errors interface{} `json:"errors,omitempty"`
+}
+
+func New(status int, code, detail string) Problem {
+	return Problem{
+		Type:   typePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// CodeForStatus возвращает общий код для HTTP-статуса
+func CodeForStatus(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// Write отправляет problem+json. request_id берется из заголовка ответа,
+// который уже выставил logging.RequestIDMiddleware, — по нему поддержка найдет запрос в логах.
+func Write(w http.ResponseWriter, p Problem) {
+	if p.RequestID == "" {
+		p.RequestID = w.Header().Get("X-Request-ID")
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Error — замена http.Error: тот же порядок аргументов, общий код по статусу
+func Error(w http.ResponseWriter, detail string, status int) {
+	Write(w, New(status, CodeForStatus(status), detail))
+}
@@ -0,0 +1,117 @@
+// Package realtime доставляет обновления в открытые сессии пользователя (SSE).
+// Сообщения идут через Redis pub/sub, поэтому доходят до клиентов на любом экземпляре сервиса.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	channelPrefix = "realtime:user:"
+
+	MessageBalance          = "balance"
+	MessageTransferSent     = "transfer.sent"
+	MessageTransferReceived = "transfer.received"
+	MessageAccountStatus    = "account.status"
+
+	// Медленный клиент, не успевший забрать сообщения из буфера, теряет новые
+	bufferSize = 32
+)
+
+type Message struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type Hub struct {
+	client *redis.Client
+
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[chan Message]struct{}
+}
+
+func NewHub(client *redis.Client) *Hub {
+	return &Hub{client: client, subscribers: make(map[uuid.UUID]map[chan Message]struct{})}
+}
+
+// Publish отправляет сообщение всем сессиям пользователя на всех экземплярах
+func (h *Hub) Publish(ctx context.Context, userID uuid.UUID, msgType string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	msg, err := json.Marshal(Message{Type: msgType, Data: raw})
+	if err != nil {
+		return err
+	}
+	return h.client.Publish(ctx, channelPrefix+userID.String(), msg).Err()
+}
+
+// Subscribe регистрирует сессию пользователя. Вызывающий обязан вызвать функцию отписки.
+func (h *Hub) Subscribe(userID uuid.UUID) (<-chan Message, func()) {
+	ch := make(chan Message, bufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan Message]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Run слушает Redis и раздает сообщения локальным сессиям, пока не отменен контекст
+func (h *Hub) Run(ctx context.Context) {
+	pubsub := h.client.PSubscribe(ctx, channelPrefix+"*")
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-ch:
+			if !ok {
+				return
+			}
+			h.dispatch(m)
+		}
+	}
+}
+
+func (h *Hub) dispatch(m *redis.Message) {
+	userID, err := uuid.Parse(strings.TrimPrefix(m.Channel, channelPrefix))
+	if err != nil {
+		return
+	}
+
+	var msg Message
+	if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+		slog.Warn("realtime: malformed message", "channel", m.Channel, "error", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscribers[userID] {
+		select {
+		case sub <- msg:
+		default:
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Ошибки проверки счетов при движении денег
+var (
+	ErrAccountNotFound   = errors.New("account not found")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrAccountFrozen     = errors.New("account is frozen")
+	ErrAccountClosed     = errors.New("account is closed")
+	ErrDebitBlocked      = errors.New("debits are blocked on this account")
+	ErrCreditBlocked     = errors.New("credits are blocked on this account")
+)
+
+func checkDebitAllowed(status string) error {
+	switch status {
+	case models.AccountStatusActive, models.AccountStatusCreditBlocked:
+		return nil
+	case models.AccountStatusClosed:
+		return ErrAccountClosed
+	case models.AccountStatusFrozen:
+		return ErrAccountFrozen
+	default:
+		return ErrDebitBlocked
+	}
+}
+
+func checkCreditAllowed(status string) error {
+	switch status {
+	case models.AccountStatusActive, models.AccountStatusDebitBlocked:
+		return nil
+	case models.AccountStatusClosed:
+		return ErrAccountClosed
+	case models.AccountStatusFrozen:
+		return ErrAccountFrozen
+	default:
+		return ErrCreditBlocked
+	}
+}
+
+func (r *Repository) GetAccountByID(ctx context.Context, id uuid.UUID) (*models.Account, error) {
+	var account models.Account
+	err := r.db.QueryRowContext(ctx, `
+        SELECT id, user_id, account_number, balance, held_balance, status
+        FROM accounts
+        WHERE id = $1
+    `, id).Scan(&account.ID, &account.UserID, &account.Number, &account.Balance, &account.HeldBalance, &account.Status)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// SetAccountStatus меняет статус счета и записывает, кто и почему это сделал.
+// Закрыть счет этим методом можно только с нулевым остатком, см. CloseAccount.
+func (r *Repository) SetAccountStatus(ctx context.Context, accountID uuid.UUID, status string, changedBy uuid.UUID, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.setAccountStatusTx(ctx, tx, accountID, status, changedBy, reason); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CloseAccount закрывает счет. Если на нем остались деньги, они переводятся на sweepTo
+// в той же транзакции; без sweepTo закрыть можно только пустой счет.
+func (r *Repository) CloseAccount(ctx context.Context, accountID uuid.UUID, sweepTo *uuid.UUID, changedBy uuid.UUID, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var balance, held float64
+	err = tx.QueryRowContext(ctx, "SELECT balance, held_balance FROM accounts WHERE id = $1 FOR UPDATE", accountID).Scan(&balance, &held)
+	if err == sql.ErrNoRows {
+		return ErrAccountNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if held > 0 {
+		return fmt.Errorf("account has active holds of %.2f", held)
+	}
+	if balance > 0 {
+		if sweepTo == nil {
+			return fmt.Errorf("account balance must be zero or swept to another account before closing")
+		}
+		details := models.TransferDetails{Memo: "Перевод остатка при закрытии счета"}
+		if _, err := r.settleTx(ctx, tx, accountID, *sweepTo, balance, "RUB", details); err != nil {
+			return fmt.Errorf("failed to sweep balance: %w", err)
+		}
+	}
+
+	if err := r.setAccountStatusTx(ctx, tx, accountID, models.AccountStatusClosed, changedBy, reason); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) setAccountStatusTx(ctx context.Context, tx *sql.Tx, accountID uuid.UUID, status string, changedBy uuid.UUID, reason string) error {
+	var (
+		oldStatus     string
+		balance, held float64
+	)
+	err := tx.QueryRowContext(ctx, `
+        SELECT status, balance, held_balance FROM accounts WHERE id = $1 FOR UPDATE
+    `, accountID).Scan(&oldStatus, &balance, &held)
+	if err == sql.ErrNoRows {
+		return ErrAccountNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if oldStatus == models.AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	if oldStatus == status {
+		return fmt.Errorf("account is already %s", status)
+	}
+	if status == models.AccountStatusClosed && (balance != 0 || held != 0) {
+		return fmt.Errorf("account balance must be zero before closing")
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE accounts SET status = $1 WHERE id = $2", status, accountID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO account_status_history (account_id, old_status, new_status, changed_by, reason)
+        VALUES ($1, $2, $3, $4, $5)
+    `, accountID, oldStatus, status, changedBy, reason)
+	if err != nil {
+		return err
+	}
+
+	data := models.AccountStatusEventData{
+		AccountID: accountID,
+		OldStatus: oldStatus,
+		NewStatus: status,
+		Reason:    reason,
+	}
+	if err := r.addOutboxEventTx(ctx, tx, models.AggregateAccount, accountID, models.EventAccountStatusChanged, data); err != nil {
+		return err
+	}
+
+	var eventType string
+	switch {
+	case status == models.AccountStatusFrozen:
+		eventType = models.EventAccountFrozen
+	case status == models.AccountStatusClosed:
+		eventType = models.EventAccountClosed
+	case oldStatus == models.AccountStatusFrozen && status == models.AccountStatusActive:
+		eventType = models.EventAccountUnfrozen
+	default:
+		return nil
+	}
+	return r.enqueueAccountWebhookTx(ctx, tx, accountID, eventType, data)
+}
+
+func (r *Repository) GetAccountStatusHistory(ctx context.Context, accountID uuid.UUID) ([]models.AccountStatusChange, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, account_id, old_status, new_status, changed_by, reason, created_at
+        FROM account_status_history
+        WHERE account_id = $1
+        ORDER BY created_at DESC
+    `, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.AccountStatusChange{}
+	for rows.Next() {
+		var c models.AccountStatusChange
+		if err := rows.Scan(&c.ID, &c.AccountID, &c.OldStatus, &c.NewStatus, &c.ChangedBy, &c.Reason, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrAliasTaken — псевдоним уже подтвержден другим пользователем
+	ErrAliasTaken = errors.New("alias is already taken")
+	// ErrAliasCodeLimited — коды подтверждения запрашиваются слишком часто
+	ErrAliasCodeLimited = errors.New("too many verification codes requested, try again later")
+	// ErrAliasLocked — попытки ввода кода исчерпаны, псевдоним заблокирован на время
+	ErrAliasLocked = errors.New("alias is locked after too many failed attempts, try again later")
+)
+
+// AliasCodeLimits ограничивает выдачу и проверку кодов подтверждения
+type AliasCodeLimits struct {
+	// Cooldown — пауза между кодами одному пользователю на один номер
+	Cooldown time.Duration
+	// PerValue и PerUser — кодов за сутки на номер (от всех пользователей) и пользователю (на все номера)
+	PerValue int
+	PerUser  int
+	// MaxAttempts неверных вводов блокируют псевдоним на LockDuration
+	MaxAttempts  int
+	LockDuration time.Duration
+}
+
+const aliasColumns = `id, user_id, type, value, verified, created_at, verified_at`
+
+func scanAlias(row interface{ Scan(...interface{}) error }) (*models.Alias, error) {
+	var a models.Alias
+	err := row.Scan(&a.ID, &a.UserID, &a.Type, &a.Value, &a.Verified, &a.CreatedAt, &a.VerifiedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// CreateAlias добавляет неподтвержденный псевдоним с хэшем кода подтверждения и записывает
+// отправку кода. Повторный запрос обновляет код, но не сбрасывает счетчик неверных вводов.
+func (r *Repository) CreateAlias(ctx context.Context, userID uuid.UUID, aliasType, value, codeHash string, codeExpiresAt time.Time, limits AliasCodeLimits) (*models.Alias, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Запросы кодов на один номер выполняются по очереди, чтобы лимиты нельзя было обойти параллельно
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1 || ':' || $2))", aliasType, value); err != nil {
+		return nil, err
+	}
+
+	var lockedUntil sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+        SELECT locked_until FROM aliases
+        WHERE user_id = $1 AND type = $2 AND value = $3
+    `, userID, aliasType, value).Scan(&lockedUntil)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if lockedUntil.Valid && lockedUntil.Time.After(time.Now()) {
+		return nil, ErrAliasLocked
+	}
+
+	var (
+		perValue, perUser int
+		lastSent          sql.NullTime
+	)
+	err = tx.QueryRowContext(ctx, `
+        SELECT COUNT(*) FILTER (WHERE type = $2 AND value = $3),
+               COUNT(*) FILTER (WHERE user_id = $1),
+               MAX(created_at) FILTER (WHERE user_id = $1 AND type = $2 AND value = $3)
+        FROM alias_code_sends
+        WHERE (user_id = $1 OR (type = $2 AND value = $3))
+          AND created_at >= CURRENT_TIMESTAMP - INTERVAL '1 day'
+    `, userID, aliasType, value).Scan(&perValue, &perUser, &lastSent)
+	if err != nil {
+		return nil, err
+	}
+	if perValue >= limits.PerValue || perUser >= limits.PerUser ||
+		(lastSent.Valid && time.Since(lastSent.Time) < limits.Cooldown) {
+		return nil, ErrAliasCodeLimited
+	}
+
+	// Истекшая блокировка снимается вместе со счетчиком
+	a, err := scanAlias(tx.QueryRowContext(ctx, `
+        INSERT INTO aliases (user_id, type, value, code_hash, code_expires_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id, type, value) DO UPDATE
+        SET code_hash = EXCLUDED.code_hash, code_expires_at = EXCLUDED.code_expires_at,
+            attempts = CASE WHEN aliases.locked_until IS NULL THEN aliases.attempts ELSE 0 END,
+            locked_until = NULL
+        WHERE NOT aliases.verified
+        RETURNING `+aliasColumns+`
+    `, userID, aliasType, value, codeHash, codeExpiresAt))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("alias is already verified")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO alias_code_sends (user_id, type, value)
+        VALUES ($1, $2, $3)
+    `, userID, aliasType, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *Repository) GetAliasesByUser(ctx context.Context, userID uuid.UUID) ([]models.Alias, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+aliasColumns+`
+        FROM aliases
+        WHERE user_id = $1
+        ORDER BY type, value
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []models.Alias{}
+	for rows.Next() {
+		a, err := scanAlias(rows)
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, *a)
+	}
+	return aliases, rows.Err()
+}
+
+// GetVerifiedAlias ищет действующего владельца псевдонима
+func (r *Repository) GetVerifiedAlias(ctx context.Context, aliasType, value string) (*models.Alias, error) {
+	a, err := scanAlias(r.db.QueryRowContext(ctx, `
+        SELECT `+aliasColumns+`
+        FROM aliases
+        WHERE type = $1 AND value = $2 AND verified
+    `, aliasType, value))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// VerifyAlias сверяет хэш кода и подтверждает псевдоним. Если псевдоним был подтвержден
+// другим пользователем (например, номер телефона перешел к новому владельцу), он переходит
+// к тому, кто подтвердил его последним, а смена владельца записывается в историю.
+// После limits.MaxAttempts неверных вводов псевдоним блокируется на limits.LockDuration.
+func (r *Repository) VerifyAlias(ctx context.Context, userID, id uuid.UUID, codeHash string, limits AliasCodeLimits) (*models.Alias, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		aliasType, value, storedHash string
+		verified                     bool
+		expiresAt, lockedUntil       sql.NullTime
+	)
+	err = tx.QueryRowContext(ctx, `
+        SELECT type, value, verified, COALESCE(code_hash, ''), code_expires_at, locked_until
+        FROM aliases
+        WHERE id = $1 AND user_id = $2
+        FOR UPDATE
+    `, id, userID).Scan(&aliasType, &value, &verified, &storedHash, &expiresAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("alias not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if verified {
+		return nil, fmt.Errorf("alias is already verified")
+	}
+	if lockedUntil.Valid && lockedUntil.Time.After(time.Now()) {
+		return nil, ErrAliasLocked
+	}
+	if storedHash == "" || !expiresAt.Valid || expiresAt.Time.Before(time.Now()) {
+		return nil, fmt.Errorf("verification code has expired, request a new code")
+	}
+	if storedHash != codeHash {
+		_, err := tx.ExecContext(ctx, `
+            UPDATE aliases
+            SET attempts = attempts + 1,
+                locked_until = CASE WHEN attempts + 1 >= $2 THEN CURRENT_TIMESTAMP + $3::float8 * INTERVAL '1 second' END,
+                code_hash = CASE WHEN attempts + 1 >= $2 THEN NULL ELSE code_hash END
+            WHERE id = $1
+        `, id, limits.MaxAttempts, limits.LockDuration.Seconds())
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("invalid verification code")
+	}
+
+	var previousOwner uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+        DELETE FROM aliases
+        WHERE type = $1 AND value = $2 AND verified AND user_id <> $3
+        RETURNING user_id
+    `, aliasType, value, userID).Scan(&previousOwner)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO alias_ownership_changes (type, value, old_user_id, new_user_id)
+            VALUES ($1, $2, $3, $4)
+        `, aliasType, value, previousOwner, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a, err := scanAlias(tx.QueryRowContext(ctx, `
+        UPDATE aliases
+        SET verified = TRUE, verified_at = CURRENT_TIMESTAMP, code_hash = NULL, code_expires_at = NULL, attempts = 0
+        WHERE id = $1
+        RETURNING `+aliasColumns+`
+    `, id))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, ErrAliasTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ClaimAlias сразу подтверждает псевдоним, не требующий кода (например, имя пользователя),
+// если он никем не занят
+func (r *Repository) ClaimAlias(ctx context.Context, userID uuid.UUID, aliasType, value string) (*models.Alias, error) {
+	a, err := scanAlias(r.db.QueryRowContext(ctx, `
+        INSERT INTO aliases (user_id, type, value, verified, verified_at)
+        VALUES ($1, $2, $3, TRUE, CURRENT_TIMESTAMP)
+        ON CONFLICT (user_id, type, value) DO UPDATE
+        SET verified = TRUE, verified_at = COALESCE(aliases.verified_at, CURRENT_TIMESTAMP)
+        RETURNING `+aliasColumns+`
+    `, userID, aliasType, value))
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, ErrAliasTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DeleteAlias удаляет псевдоним. Заблокированный псевдоним удалить нельзя, пока не истечет
+// блокировка: иначе удаление и повторное добавление сбрасывали бы счетчик попыток.
+func (r *Repository) DeleteAlias(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	var locked bool
+	err := r.db.QueryRowContext(ctx, `
+        WITH target AS (
+            SELECT id, COALESCE(locked_until > CURRENT_TIMESTAMP, FALSE) AS locked
+            FROM aliases
+            WHERE id = $1 AND user_id = $2
+        ), deleted AS (
+            DELETE FROM aliases
+            WHERE id IN (SELECT id FROM target WHERE NOT locked)
+        )
+        SELECT locked FROM target
+    `, id, userID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if locked {
+		return false, ErrAliasLocked
+	}
+	return true, nil
+}
@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrBatchAlreadyExecuted — строки пакета уже провел другой обработчик
+var ErrBatchAlreadyExecuted = errors.New("transfer batch already executed")
+
+const transferBatchColumns = `id, user_id, account_id, mode, status, total_lines, succeeded, failed, created_at, completed_at, COALESCE(error, '')`
+
+func scanTransferBatch(row interface{ Scan(...any) error }) (*models.TransferBatch, error) {
+	var batch models.TransferBatch
+	err := row.Scan(&batch.ID, &batch.UserID, &batch.AccountID, &batch.Mode, &batch.Status,
+		&batch.TotalLines, &batch.Succeeded, &batch.Failed, &batch.CreatedAt, &batch.CompletedAt, &batch.Error)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *Repository) CreateTransferBatch(ctx context.Context, userID, accountID uuid.UUID, mode string, items []models.TransferBatchItem) (*models.TransferBatch, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	batch := models.TransferBatch{
+		UserID:     userID,
+		AccountID:  accountID,
+		Mode:       mode,
+		Status:     models.BatchStatusPending,
+		TotalLines: len(items),
+	}
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO transfer_batches (user_id, account_id, mode, status, total_lines)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `, userID, accountID, mode, batch.Status, batch.TotalLines).Scan(&batch.ID, &batch.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO transfer_batch_items (batch_id, line_no, to_email, to_account_id, amount, currency, memo, reference, status)
+            VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9)
+        `, batch.ID, item.LineNo, item.ToEmail, item.ToAccountID, item.Amount, item.Currency, item.Memo, item.Reference, models.BatchItemPending)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// GetTransferBatch возвращает пакет вместе с построчными результатами
+func (r *Repository) GetTransferBatch(ctx context.Context, userID, id uuid.UUID) (*models.TransferBatch, error) {
+	batch, err := scanTransferBatch(r.db.QueryRowContext(ctx, `
+        SELECT `+transferBatchColumns+`
+        FROM transfer_batches
+        WHERE id = $1 AND user_id = $2
+    `, id, userID))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Items, err = r.getTransferBatchItems(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ClaimTransferBatch забирает самый старый необработанный пакет на время аренды.
+// Пакет в статусе processing с истекшей арендой — прерванный перезапуском, его строки
+// в статусе pending обрабатываются заново. Возвращает nil, если очередь пуста.
+func (r *Repository) ClaimTransferBatch(ctx context.Context, lease time.Duration) (*models.TransferBatch, error) {
+	batch, err := scanTransferBatch(r.db.QueryRowContext(ctx, `
+        UPDATE transfer_batches
+        SET status = $1, locked_until = CURRENT_TIMESTAMP + $2::float8 * INTERVAL '1 second'
+        WHERE id = (
+            SELECT id FROM transfer_batches
+            WHERE status IN ('pending', 'processing')
+              AND (locked_until IS NULL OR locked_until <= CURRENT_TIMESTAMP)
+            ORDER BY created_at
+            LIMIT 1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING `+transferBatchColumns+`
+    `, models.BatchStatusProcessing, lease.Seconds()))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Items, err = r.getTransferBatchItems(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ReleaseTransferBatch снимает аренду с недообработанного пакета при остановке,
+// чтобы после перезапуска его сразу подхватили
+func (r *Repository) ReleaseTransferBatch(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE transfer_batches SET locked_until = NULL WHERE id = $1", id)
+	return err
+}
+
+func (r *Repository) getTransferBatchItems(ctx context.Context, batchID uuid.UUID) ([]models.TransferBatchItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT line_no, to_email, to_account_id, amount, currency, COALESCE(memo, ''), COALESCE(reference, ''),
+               status, transfer_id, COALESCE(error, '')
+        FROM transfer_batch_items
+        WHERE batch_id = $1
+        ORDER BY line_no
+    `, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.TransferBatchItem{}
+	for rows.Next() {
+		var item models.TransferBatchItem
+		err := rows.Scan(&item.LineNo, &item.ToEmail, &item.ToAccountID, &item.Amount, &item.Currency,
+			&item.Memo, &item.Reference, &item.Status, &item.TransferID, &item.Error)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *Repository) SetTransferBatchStatus(ctx context.Context, id uuid.UUID, status string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE transfer_batches SET status = $1 WHERE id = $2", status, id)
+	return err
+}
+
+func (r *Repository) SetTransferBatchItemResult(ctx context.Context, batchID uuid.UUID, lineNo int, status string, transferID *uuid.UUID, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE transfer_batch_items
+        SET status = $1, transfer_id = $2, error = NULLIF($3, '')
+        WHERE batch_id = $4 AND line_no = $5
+    `, status, transferID, errMsg, batchID, lineNo)
+	return err
+}
+
+// FinishTransferBatch пересчитывает итоги по строкам и выставляет финальный статус
+func (r *Repository) FinishTransferBatch(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE transfer_batches b
+        SET succeeded = s.succeeded,
+            failed = s.failed,
+            status = CASE
+                WHEN s.failed = 0 THEN 'completed'
+                WHEN s.succeeded = 0 THEN 'failed'
+                ELSE 'partially_completed'
+            END,
+            completed_at = CURRENT_TIMESTAMP,
+            locked_until = NULL
+        FROM (
+            SELECT COUNT(*) FILTER (WHERE status = 'completed') AS succeeded,
+                   COUNT(*) FILTER (WHERE status <> 'completed') AS failed
+            FROM transfer_batch_items
+            WHERE batch_id = $1
+        ) s
+        WHERE b.id = $1
+    `, id)
+	return err
+}
+
+// FailTransferBatch отмечает ошибку всего пакета: непроведенные строки пропускаются,
+// а пакет завершается со статусом failed
+func (r *Repository) FailTransferBatch(ctx context.Context, id uuid.UUID, errMsg string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE transfer_batch_items
+        SET status = $1, error = 'batch rolled back'
+        WHERE batch_id = $2 AND status = $3
+    `, models.BatchItemSkipped, id, models.BatchItemPending)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE transfer_batches SET error = $1 WHERE id = $2", errMsg, id)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return r.FinishTransferBatch(ctx, id)
+}
+
+// ExecuteTransferBatchLine проводит строку пакета в режиме best_effort вместе с записью
+// результата, поэтому строка не проводится повторно после перезапуска.
+// Для уже обработанной строки возвращает ErrBatchAlreadyExecuted.
+func (r *Repository) ExecuteTransferBatchLine(ctx context.Context, batchID, from uuid.UUID, item models.TransferBatchItem, amount float64) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, `
+        SELECT status FROM transfer_batch_items
+        WHERE batch_id = $1 AND line_no = $2
+        FOR UPDATE
+    `, batchID, item.LineNo).Scan(&status)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if status != models.BatchItemPending {
+		return uuid.Nil, ErrBatchAlreadyExecuted
+	}
+
+	details := models.TransferDetails{Memo: item.Memo, Reference: item.Reference}
+	transferID, err := r.transferTx(ctx, tx, from, item.ToAccountID, amount, item.Currency, details)
+	if err != nil {
+		return uuid.Nil, r.rollbackOnLimit(ctx, tx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE transfer_batch_items
+        SET status = $1, transfer_id = $2
+        WHERE batch_id = $3 AND line_no = $4
+    `, models.BatchItemCompleted, transferID, batchID, item.LineNo)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to record batch line: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return transferID, nil
+}
+
+// ExecuteTransferBatchAtomic проводит все строки пакета в одной транзакции.
+// amounts содержит суммы в RUB в том же порядке, что и items.
+// При ошибке ничего не списывается, а возвращается номер строки, на которой она произошла;
+// 0 — ошибка всего пакета (например, при фиксации транзакции).
+func (r *Repository) ExecuteTransferBatchAtomic(ctx context.Context, batchID, from uuid.UUID, items []models.TransferBatchItem, amounts []float64) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Блокировка пакета не дает двум обработчикам провести его одновременно
+	var executed bool
+	err = tx.QueryRowContext(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM transfer_batch_items WHERE batch_id = b.id AND status <> $2
+        )
+        FROM transfer_batches b
+        WHERE b.id = $1
+        FOR UPDATE OF b
+    `, batchID, models.BatchItemPending).Scan(&executed)
+	if err != nil {
+		return 0, err
+	}
+	if executed {
+		return 0, ErrBatchAlreadyExecuted
+	}
+
+	for i, item := range items {
+		details := models.TransferDetails{Memo: item.Memo, Reference: item.Reference}
+		transferID, err := r.transferTx(ctx, tx, from, item.ToAccountID, amounts[i], item.Currency, details)
+		if err != nil {
+			return item.LineNo, r.rollbackOnLimit(ctx, tx, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            UPDATE transfer_batch_items
+            SET status = $1, transfer_id = $2
+            WHERE batch_id = $3 AND line_no = $4
+        `, models.BatchItemCompleted, transferID, batchID, item.LineNo)
+		if err != nil {
+			return item.LineNo, fmt.Errorf("failed to record batch line: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const beneficiaryColumns = `
+        b.id, b.user_id, b.account_id, u.email, b.nickname, b.is_favorite, b.password_confirmed, b.created_at
+        FROM beneficiaries b
+        JOIN accounts a ON b.account_id = a.id
+        JOIN users u ON a.user_id = u.id`
+
+func scanBeneficiary(row interface{ Scan(...interface{}) error }) (*models.Beneficiary, error) {
+	var b models.Beneficiary
+	err := row.Scan(&b.ID, &b.UserID, &b.AccountID, &b.Email, &b.Nickname, &b.IsFavorite, &b.PasswordConfirmed, &b.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *Repository) CreateBeneficiary(ctx context.Context, userID, accountID uuid.UUID, nickname string, isFavorite bool) (*models.Beneficiary, error) {
+	var id uuid.UUID
+	err := r.db.QueryRowContext(ctx, `
+        INSERT INTO beneficiaries (user_id, account_id, nickname, is_favorite, password_confirmed)
+        VALUES ($1, $2, $3, $4, TRUE)
+        ON CONFLICT (user_id, account_id) DO UPDATE
+        SET nickname = EXCLUDED.nickname, is_favorite = EXCLUDED.is_favorite, password_confirmed = TRUE
+        RETURNING id
+    `, userID, accountID, nickname, isFavorite).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetBeneficiary(ctx, userID, id)
+}
+
+func (r *Repository) GetBeneficiary(ctx context.Context, userID, id uuid.UUID) (*models.Beneficiary, error) {
+	b, err := scanBeneficiary(r.db.QueryRowContext(ctx, `
+        SELECT`+beneficiaryColumns+`
+        WHERE b.user_id = $1 AND b.id = $2
+    `, userID, id))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r *Repository) GetBeneficiaryByAccount(ctx context.Context, userID, accountID uuid.UUID) (*models.Beneficiary, error) {
+	b, err := scanBeneficiary(r.db.QueryRowContext(ctx, `
+        SELECT`+beneficiaryColumns+`
+        WHERE b.user_id = $1 AND b.account_id = $2
+    `, userID, accountID))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Избранные контакты идут первыми
+func (r *Repository) GetBeneficiariesByUser(ctx context.Context, userID uuid.UUID) ([]models.Beneficiary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT`+beneficiaryColumns+`
+        WHERE b.user_id = $1
+        ORDER BY b.is_favorite DESC, b.nickname
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	beneficiaries := []models.Beneficiary{}
+	for rows.Next() {
+		b, err := scanBeneficiary(rows)
+		if err != nil {
+			return nil, err
+		}
+		beneficiaries = append(beneficiaries, *b)
+	}
+	return beneficiaries, rows.Err()
+}
+
+func (r *Repository) UpdateBeneficiary(ctx context.Context, userID, id uuid.UUID, nickname *string, isFavorite *bool) (*models.Beneficiary, error) {
+	result, err := r.db.ExecContext(ctx, `
+        UPDATE beneficiaries
+        SET nickname = COALESCE($3, nickname),
+            is_favorite = COALESCE($4, is_favorite)
+        WHERE user_id = $1 AND id = $2
+    `, userID, id, nickname, isFavorite)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, nil
+	}
+	return r.GetBeneficiary(ctx, userID, id)
+}
+
+func (r *Repository) DeleteBeneficiary(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM beneficiaries WHERE user_id = $1 AND id = $2", userID, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
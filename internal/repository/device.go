@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// RecordLogin запоминает устройство, с которого вошел пользователь. Если устройство новое,
+// а до этого у пользователя уже были другие, записывается событие о входе с нового устройства.
+// Первое устройство (регистрация) событий не порождает.
+func (r *Repository) RecordLogin(ctx context.Context, userID uuid.UUID, fingerprint, userAgent, ip string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var inserted bool
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO user_devices (user_id, fingerprint, user_agent, last_ip)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (user_id, fingerprint) DO UPDATE
+        SET last_ip = EXCLUDED.last_ip, last_seen_at = CURRENT_TIMESTAMP
+        RETURNING xmax = 0
+    `, userID, fingerprint, userAgent, ip).Scan(&inserted)
+	if err != nil {
+		return err
+	}
+
+	if inserted {
+		var known bool
+		err = tx.QueryRowContext(ctx, `
+            SELECT EXISTS (SELECT 1 FROM user_devices WHERE user_id = $1 AND fingerprint <> $2)
+        `, userID, fingerprint).Scan(&known)
+		if err != nil {
+			return err
+		}
+		if known {
+			event := models.NewDeviceLoginEventData{UserID: userID, UserAgent: userAgent, IP: ip, CreatedAt: time.Now()}
+			if err := r.addOutboxEventTx(ctx, tx, models.AggregateUser, userID, models.EventNewDeviceLogin, event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
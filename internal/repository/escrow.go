@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrEscrowState — действие недопустимо в текущем состоянии сделки
+var ErrEscrowState = errors.New("invalid escrow state")
+
+const escrowSelect = `
+        SELECT e.id, e.from_account_id, e.to_account_id, u1.email, u2.email, e.hold_id, e.amount, e.amount_rub, e.currency,
+               COALESCE(e.memo, ''), e.status, COALESCE(e.resolution, ''), e.sender_confirmed, e.recipient_confirmed,
+               e.deadline, e.transfer_id, e.created_at, e.resolved_at
+        FROM escrow_transfers e
+        JOIN accounts a1 ON e.from_account_id = a1.id
+        JOIN users u1 ON a1.user_id = u1.id
+        JOIN accounts a2 ON e.to_account_id = a2.id
+        JOIN users u2 ON a2.user_id = u2.id`
+
+func scanEscrow(row interface{ Scan(...interface{}) error }) (*models.Escrow, error) {
+	var e models.Escrow
+	err := row.Scan(&e.ID, &e.FromAccountID, &e.ToAccountID, &e.FromEmail, &e.ToEmail, &e.HoldID, &e.Amount, &e.AmountRUB, &e.Currency,
+		&e.Memo, &e.Status, &e.Resolution, &e.SenderConfirmed, &e.RecipientConfirmed,
+		&e.Deadline, &e.TransferID, &e.CreatedAt, &e.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// CreateEscrow резервирует сумму на счете отправителя до подтверждения сторонами или дедлайна.
+// amount — в валюте сделки, amountRUB — удерживаемая сумма в рублях.
+func (r *Repository) CreateEscrow(ctx context.Context, from, to uuid.UUID, amount, amountRUB float64, currency, memo string, deadline time.Time) (*models.Escrow, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	holdID, err := r.createHoldTx(ctx, tx, from, amountRUB, models.HoldReasonEscrow, &deadline)
+	if err != nil {
+		return nil, r.rollbackOnLimit(ctx, tx, err)
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO escrow_transfers (from_account_id, to_account_id, hold_id, amount, amount_rub, currency, memo, status, deadline)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)
+        RETURNING id
+    `, from, to, holdID, amount, amountRUB, currency, memo, models.EscrowStatusHeld, deadline).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetEscrow(ctx, id)
+}
+
+func (r *Repository) GetEscrow(ctx context.Context, id uuid.UUID) (*models.Escrow, error) {
+	e, err := scanEscrow(r.db.QueryRowContext(ctx, escrowSelect+`
+        WHERE e.id = $1
+    `, id))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *Repository) GetEscrowsByAccount(ctx context.Context, accountID uuid.UUID) ([]models.Escrow, error) {
+	rows, err := r.db.QueryContext(ctx, escrowSelect+`
+        WHERE e.from_account_id = $1 OR e.to_account_id = $1
+        ORDER BY e.created_at DESC
+    `, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	escrows := []models.Escrow{}
+	for rows.Next() {
+		e, err := scanEscrow(rows)
+		if err != nil {
+			return nil, err
+		}
+		escrows = append(escrows, *e)
+	}
+	return escrows, rows.Err()
+}
+
+// lockEscrowTx блокирует сделку и проверяет, что она в ожидаемом статусе
+func (r *Repository) lockEscrowTx(ctx context.Context, tx *sql.Tx, id uuid.UUID, status string) (*models.Escrow, error) {
+	e, err := scanEscrow(tx.QueryRowContext(ctx, escrowSelect+`
+        WHERE e.id = $1
+        FOR UPDATE OF e
+    `, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("escrow not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != status {
+		return nil, fmt.Errorf("%w: escrow is %s", ErrEscrowState, e.Status)
+	}
+	return e, nil
+}
+
+// ConfirmEscrow отмечает подтверждение стороны сделки. Когда подтвердили обе стороны,
+// удержание списывается и деньги переводятся получателю.
+func (r *Repository) ConfirmEscrow(ctx context.Context, id, accountID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	e, err := r.lockEscrowTx(ctx, tx, id, models.EscrowStatusHeld)
+	if err != nil {
+		return err
+	}
+
+	switch accountID {
+	case e.FromAccountID:
+		e.SenderConfirmed = true
+	case e.ToAccountID:
+		e.RecipientConfirmed = true
+	default:
+		return fmt.Errorf("escrow not found")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE escrow_transfers SET sender_confirmed = $1, recipient_confirmed = $2 WHERE id = $3
+    `, e.SenderConfirmed, e.RecipientConfirmed, id)
+	if err != nil {
+		return err
+	}
+
+	if e.SenderConfirmed && e.RecipientConfirmed {
+		if err := r.releaseEscrowTx(ctx, tx, e, models.EscrowResolutionConfirmed); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RefundEscrow отменяет сделку по запросу отправителя и возвращает деньги в его доступный
+// остаток. Подтверждение получателя проверяется под блокировкой сделки: после него
+// вернуть деньги можно только через спор.
+func (r *Repository) RefundEscrow(ctx context.Context, id, senderAccountID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	e, err := r.lockEscrowTx(ctx, tx, id, models.EscrowStatusHeld)
+	if err != nil {
+		return err
+	}
+	if e.FromAccountID != senderAccountID {
+		return fmt.Errorf("only the sender can cancel an escrow")
+	}
+	if e.RecipientConfirmed {
+		return fmt.Errorf("%w: recipient has already confirmed, open a dispute instead", ErrEscrowState)
+	}
+
+	if err := r.refundEscrowTx(ctx, tx, e, models.EscrowResolutionCancelled); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DisputeEscrow замораживает сделку до решения администратора: деньги остаются
+// в удержании, дедлайн на спорную сделку не действует
+func (r *Repository) DisputeEscrow(ctx context.Context, id, accountID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	e, err := r.lockEscrowTx(ctx, tx, id, models.EscrowStatusHeld)
+	if err != nil {
+		return err
+	}
+	if accountID != e.FromAccountID && accountID != e.ToAccountID {
+		return fmt.Errorf("escrow not found")
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE escrow_transfers SET status = $1 WHERE id = $2", models.EscrowStatusDisputed, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ResolveEscrowDispute завершает спорную сделку: переводит деньги получателю
+// или возвращает их отправителю
+func (r *Repository) ResolveEscrowDispute(ctx context.Context, id uuid.UUID, outcome string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	e, err := r.lockEscrowTx(ctx, tx, id, models.EscrowStatusDisputed)
+	if err != nil {
+		return err
+	}
+
+	switch outcome {
+	case models.EscrowOutcomeRelease:
+		err = r.releaseEscrowTx(ctx, tx, e, models.EscrowResolutionDisputed)
+	case models.EscrowOutcomeRefund:
+		err = r.refundEscrowTx(ctx, tx, e, models.EscrowResolutionDisputed)
+	default:
+		err = fmt.Errorf("unknown escrow outcome: %s", outcome)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// refundEscrowTx снимает удержание и возвращает деньги в доступный остаток отправителя
+func (r *Repository) refundEscrowTx(ctx context.Context, tx *sql.Tx, e *models.Escrow, resolution string) error {
+	if _, _, err := r.releaseHoldTx(ctx, tx, e.HoldID, models.HoldStatusReleased); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+        UPDATE escrow_transfers
+        SET status = $1, resolution = $2, resolved_at = CURRENT_TIMESTAMP
+        WHERE id = $3
+    `, models.EscrowStatusRefunded, resolution, e.ID)
+	return err
+}
+
+// escrowReleaseMaxDelay — предельная пауза между попытками выпустить сделку
+const escrowReleaseMaxDelay = 6 * time.Hour
+
+// ReleaseExpiredEscrows переводит получателям сделки с истекшим дедлайном. Неудавшаяся
+// попытка откладывает сделку на 1m, 2m, 4m... но не больше escrowReleaseMaxDelay, поэтому
+// сделки, которые выпустить пока нельзя, не мешают остальным.
+func (r *Repository) ReleaseExpiredEscrows(ctx context.Context) (int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id FROM escrow_transfers
+        WHERE status = $1 AND deadline <= CURRENT_TIMESTAMP
+          AND (next_release_at IS NULL OR next_release_at <= CURRENT_TIMESTAMP)
+        ORDER BY COALESCE(next_release_at, deadline)
+        LIMIT 100
+    `, models.EscrowStatusHeld)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, id := range ids {
+		if err := r.releaseExpiredEscrow(ctx, id); err != nil {
+			slog.ErrorContext(ctx, "escrow release failed", "escrow_id", id, "error", err)
+			if err := r.deferEscrowRelease(ctx, id, err); err != nil {
+				slog.ErrorContext(ctx, "failed to defer escrow release", "escrow_id", id, "error", err)
+			}
+			continue
+		}
+		released++
+	}
+	return released, nil
+}
+
+func (r *Repository) releaseExpiredEscrow(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	e, err := r.lockEscrowTx(ctx, tx, id, models.EscrowStatusHeld)
+	if err != nil {
+		return err
+	}
+
+	if err := r.releaseEscrowTx(ctx, tx, e, models.EscrowResolutionDeadline); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deferEscrowRelease записывает неудачную попытку выпуска и время следующей
+func (r *Repository) deferEscrowRelease(ctx context.Context, id uuid.UUID, cause error) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE escrow_transfers
+        SET release_attempts = release_attempts + 1, release_error = $3,
+            next_release_at = CURRENT_TIMESTAMP + LEAST(60 * power(2, release_attempts), $4::float8) * INTERVAL '1 second'
+        WHERE id = $1 AND status = $2
+    `, id, models.EscrowStatusHeld, cause.Error(), escrowReleaseMaxDelay.Seconds())
+	return err
+}
+
+func (r *Repository) releaseEscrowTx(ctx context.Context, tx *sql.Tx, e *models.Escrow, resolution string) error {
+	if _, _, err := r.releaseHoldTx(ctx, tx, e.HoldID, models.HoldStatusCaptured); err != nil {
+		return err
+	}
+
+	transferID, err := r.settleTx(ctx, tx, e.FromAccountID, e.ToAccountID, e.AmountRUB, e.Currency, models.TransferDetails{Memo: e.Memo})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE escrow_transfers
+        SET status = $1, resolution = $2, transfer_id = $3, resolved_at = CURRENT_TIMESTAMP
+        WHERE id = $4
+    `, models.EscrowStatusReleased, resolution, transferID, e.ID)
+	return err
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func (r *Repository) CreateExpenseGroup(ctx context.Context, name string, createdBy uuid.UUID, memberIDs []uuid.UUID) (*models.ExpenseGroup, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var group models.ExpenseGroup
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO expense_groups (name, created_by)
+        VALUES ($1, $2)
+        RETURNING id, name, created_by, created_at
+    `, name, createdBy).Scan(&group.ID, &group.Name, &group.CreatedBy, &group.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range memberIDs {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO expense_group_members (group_id, user_id)
+            VALUES ($1, $2)
+            ON CONFLICT DO NOTHING
+        `, group.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetExpenseGroup(ctx, group.ID)
+}
+
+func (r *Repository) GetExpenseGroup(ctx context.Context, id uuid.UUID) (*models.ExpenseGroup, error) {
+	var group models.ExpenseGroup
+	err := r.db.QueryRowContext(ctx, `
+        SELECT id, name, created_by, created_at
+        FROM expense_groups WHERE id = $1
+    `, id).Scan(&group.ID, &group.Name, &group.CreatedBy, &group.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	group.Members, err = r.GetGroupMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *Repository) GetExpenseGroupsByUser(ctx context.Context, userID uuid.UUID) ([]models.ExpenseGroup, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT g.id, g.name, g.created_by, g.created_at
+        FROM expense_groups g
+        JOIN expense_group_members m ON m.group_id = g.id
+        WHERE m.user_id = $1
+        ORDER BY g.created_at DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.ExpenseGroup{}
+	for rows.Next() {
+		var group models.ExpenseGroup
+		if err := rows.Scan(&group.ID, &group.Name, &group.CreatedBy, &group.CreatedAt); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+func (r *Repository) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]models.GroupMember, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT u.id, u.email, u.full_name
+        FROM expense_group_members m
+        JOIN users u ON u.id = m.user_id
+        WHERE m.group_id = $1
+        ORDER BY m.joined_at, u.email
+    `, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.GroupMember{}
+	for rows.Next() {
+		var m models.GroupMember
+		if err := rows.Scan(&m.UserID, &m.Email, &m.FullName); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (r *Repository) IsGroupMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+        SELECT EXISTS(SELECT 1 FROM expense_group_members WHERE group_id = $1 AND user_id = $2)
+    `, groupID, userID).Scan(&exists)
+	return exists, err
+}
+
+func (r *Repository) AddGroupMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO expense_group_members (group_id, user_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+    `, groupID, userID)
+	return err
+}
+
+func (r *Repository) CreateExpense(ctx context.Context, expense models.Expense) (*models.Expense, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO expenses (group_id, paid_by, amount, description, split_type)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `, expense.GroupID, expense.PaidBy, expense.Amount, expense.Description, expense.SplitType).Scan(&expense.ID, &expense.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, share := range expense.Shares {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO expense_shares (expense_id, user_id, amount)
+            VALUES ($1, $2, $3)
+        `, expense.ID, share.UserID, share.Amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+func (r *Repository) GetGroupExpenses(ctx context.Context, groupID uuid.UUID) ([]models.Expense, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT e.id, e.group_id, e.paid_by, e.amount, e.description, e.split_type, e.created_at,
+               s.user_id, s.amount
+        FROM expenses e
+        JOIN expense_shares s ON s.expense_id = e.id
+        WHERE e.group_id = $1
+        ORDER BY e.created_at DESC, e.id, s.user_id
+    `, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	expenses := []models.Expense{}
+	for rows.Next() {
+		var (
+			e     models.Expense
+			share models.ExpenseShare
+		)
+		err := rows.Scan(&e.ID, &e.GroupID, &e.PaidBy, &e.Amount, &e.Description, &e.SplitType, &e.CreatedAt,
+			&share.UserID, &share.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		if n := len(expenses); n > 0 && expenses[n-1].ID == e.ID {
+			expenses[n-1].Shares = append(expenses[n-1].Shares, share)
+			continue
+		}
+		e.Shares = []models.ExpenseShare{share}
+		expenses = append(expenses, e)
+	}
+	return expenses, rows.Err()
+}
+
+// Чистый баланс участника: оплачено - доля в расходах + отправлено при расчетах - получено
+const groupNetExpr = `
+               COALESCE((SELECT SUM(e.amount) FROM expenses e WHERE e.group_id = m.group_id AND e.paid_by = m.user_id), 0)
+             - COALESCE((SELECT SUM(s.amount) FROM expense_shares s JOIN expenses e ON e.id = s.expense_id
+                         WHERE e.group_id = m.group_id AND s.user_id = m.user_id), 0)
+             + COALESCE((SELECT SUM(gs.amount) FROM group_settlements gs WHERE gs.group_id = m.group_id AND gs.from_user_id = m.user_id), 0)
+             - COALESCE((SELECT SUM(gs.amount) FROM group_settlements gs WHERE gs.group_id = m.group_id AND gs.to_user_id = m.user_id), 0)`
+
+func (r *Repository) GetGroupNetBalances(ctx context.Context, groupID uuid.UUID) ([]models.GroupBalance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT u.id, u.email, u.full_name,`+groupNetExpr+`
+        FROM expense_group_members m
+        JOIN users u ON u.id = m.user_id
+        WHERE m.group_id = $1
+        ORDER BY m.joined_at, u.email
+    `, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := []models.GroupBalance{}
+	for rows.Next() {
+		var b models.GroupBalance
+		if err := rows.Scan(&b.UserID, &b.Email, &b.FullName, &b.Net); err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
+
+// SettleGroupDebt переводит деньги между участниками и фиксирует расчет в одной транзакции
+func (r *Repository) SettleGroupDebt(ctx context.Context, groupID uuid.UUID, debt models.GroupDebt, fromAccount, toAccount uuid.UUID, details models.TransferDetails) (*models.GroupSettlement, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Блокируем группу, чтобы параллельные расчеты не погасили один долг дважды
+	_, err = tx.ExecContext(ctx, "SELECT 1 FROM expense_groups WHERE id = $1 FOR UPDATE", groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var net float64
+	err = tx.QueryRowContext(ctx, `
+        SELECT`+groupNetExpr+`
+        FROM expense_group_members m
+        WHERE m.group_id = $1 AND m.user_id = $2
+    `, groupID, debt.From).Scan(&net)
+	if err != nil {
+		return nil, err
+	}
+	if net > -debt.Amount+0.005 {
+		return nil, fmt.Errorf("group balances have changed, please retry")
+	}
+
+	transferID, err := r.transferTx(ctx, tx, fromAccount, toAccount, debt.Amount, "RUB", details)
+	if err != nil {
+		return nil, r.rollbackOnLimit(ctx, tx, err)
+	}
+
+	settlement := models.GroupSettlement{
+		GroupID:    groupID,
+		From:       debt.From,
+		To:         debt.To,
+		Amount:     debt.Amount,
+		TransferID: transferID,
+	}
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO group_settlements (group_id, from_user_id, to_user_id, amount, transfer_id)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `, groupID, debt.From, debt.To, debt.Amount, transferID).Scan(&settlement.ID, &settlement.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &settlement, nil
+}
@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createHoldTx резервирует amount на счете, если доступного остатка и дневного лимита хватает.
+// Удержание — списание по инициативе клиента, поэтому оно расходует лимит сразу.
+func (r *Repository) createHoldTx(ctx context.Context, tx *sql.Tx, accountID uuid.UUID, amount float64, reason string, expiresAt *time.Time) (uuid.UUID, error) {
+	var (
+		available float64
+		status    string
+	)
+	err := tx.QueryRowContext(ctx, "SELECT balance - held_balance, status FROM accounts WHERE id = $1 FOR UPDATE", accountID).Scan(&available, &status)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, ErrAccountNotFound
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkDebitAllowed(status); err != nil {
+		return uuid.Nil, err
+	}
+	if err := r.checkDailyLimitTx(ctx, tx, accountID, amount); err != nil {
+		return uuid.Nil, err
+	}
+	if available < amount {
+		return uuid.Nil, ErrInsufficientFunds
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE accounts SET held_balance = held_balance + $1 WHERE id = $2", amount, accountID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var holdID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO holds (account_id, amount, status, reason, expires_at)
+        VALUES ($1, $2, 'active', $3, $4)
+        RETURNING id
+    `, accountID, amount, reason, expiresAt).Scan(&holdID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return holdID, nil
+}
+
+// releaseHoldTx снимает активное удержание и возвращает его сумму в доступный остаток.
+// status задает итоговое состояние удержания: released или captured.
+func (r *Repository) releaseHoldTx(ctx context.Context, tx *sql.Tx, holdID uuid.UUID, status string) (uuid.UUID, float64, error) {
+	var (
+		accountID uuid.UUID
+		amount    float64
+	)
+	err := tx.QueryRowContext(ctx, `
+        UPDATE holds
+        SET status = $1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND status = 'active'
+        RETURNING account_id, amount
+    `, status, holdID).Scan(&accountID, &amount)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, 0, fmt.Errorf("hold is not active")
+	}
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE accounts SET held_balance = held_balance - $1 WHERE id = $2", amount, accountID)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	return accountID, amount, nil
+}
+
+const authorizationSelect = `
+        SELECT z.id, z.hold_id, z.payer_account_id, z.payee_account_id, u1.email, u2.email, z.amount, z.amount_rub, z.captured_amount,
+               z.currency, COALESCE(z.memo, ''), z.status, z.expires_at, z.transfer_id, z.created_at, z.updated_at
+        FROM authorizations z
+        JOIN accounts a1 ON z.payer_account_id = a1.id
+        JOIN users u1 ON a1.user_id = u1.id
+        JOIN accounts a2 ON z.payee_account_id = a2.id
+        JOIN users u2 ON a2.user_id = u2.id`
+
+func scanAuthorization(row interface{ Scan(...interface{}) error }) (*models.Authorization, error) {
+	var z models.Authorization
+	err := row.Scan(&z.ID, &z.HoldID, &z.PayerAccountID, &z.PayeeAccountID, &z.PayerEmail, &z.PayeeEmail, &z.Amount, &z.AmountRUB, &z.CapturedAmount,
+		&z.Currency, &z.Memo, &z.Status, &z.ExpiresAt, &z.TransferID, &z.CreatedAt, &z.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
+
+// Authorize резервирует сумму на счете плательщика до списания, отмены или истечения срока.
+// amount — в валюте авторизации, amountRUB — удерживаемая сумма в рублях.
+func (r *Repository) Authorize(ctx context.Context, payer, payee uuid.UUID, amount, amountRUB float64, currency, memo string, expiresAt time.Time) (*models.Authorization, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	holdID, err := r.createHoldTx(ctx, tx, payer, amountRUB, models.HoldReasonAuthorization, &expiresAt)
+	if err != nil {
+		return nil, r.rollbackOnLimit(ctx, tx, err)
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO authorizations (hold_id, payer_account_id, payee_account_id, amount, amount_rub, currency, memo, status, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)
+        RETURNING id
+    `, holdID, payer, payee, amount, amountRUB, currency, memo, models.AuthorizationStatusAuthorized, expiresAt).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetAuthorization(ctx, id)
+}
+
+func (r *Repository) GetAuthorization(ctx context.Context, id uuid.UUID) (*models.Authorization, error) {
+	z, err := scanAuthorization(r.db.QueryRowContext(ctx, authorizationSelect+`
+        WHERE z.id = $1
+    `, id))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+func (r *Repository) GetAuthorizationsByAccount(ctx context.Context, accountID uuid.UUID) ([]models.Authorization, error) {
+	rows, err := r.db.QueryContext(ctx, authorizationSelect+`
+        WHERE z.payer_account_id = $1 OR z.payee_account_id = $1
+        ORDER BY z.created_at DESC
+    `, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	authorizations := []models.Authorization{}
+	for rows.Next() {
+		z, err := scanAuthorization(rows)
+		if err != nil {
+			return nil, err
+		}
+		authorizations = append(authorizations, *z)
+	}
+	return authorizations, rows.Err()
+}
+
+// lockAuthorizationTx блокирует авторизацию получателя и проверяет, что по ней еще можно списывать
+func (r *Repository) lockAuthorizationTx(ctx context.Context, tx *sql.Tx, id, payee uuid.UUID) (*models.Authorization, error) {
+	z, err := scanAuthorization(tx.QueryRowContext(ctx, authorizationSelect+`
+        WHERE z.id = $1 AND z.payee_account_id = $2
+        FOR UPDATE OF z
+    `, id, payee))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("authorization not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if z.Status != models.AuthorizationStatusAuthorized {
+		return nil, fmt.Errorf("authorization is already %s", z.Status)
+	}
+	if !z.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("authorization has expired")
+	}
+	return z, nil
+}
+
+// Capture списывает amount в валюте авторизации (не больше авторизованного) в пользу
+// получателя, остаток удержания возвращается плательщику. Сумма в рублях считается
+// пропорционально удержанию, чтобы изменение курса не влияло на списание.
+func (r *Repository) Capture(ctx context.Context, id, payee uuid.UUID, amount float64) (*models.Authorization, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	z, err := r.lockAuthorizationTx(ctx, tx, id, payee)
+	if err != nil {
+		return nil, err
+	}
+	if amount == 0 {
+		amount = z.Amount
+	}
+	if amount > z.Amount {
+		return nil, fmt.Errorf("capture amount exceeds authorized amount %.2f %s", z.Amount, z.Currency)
+	}
+	amountRUB := z.AmountRUB
+	if amount < z.Amount {
+		amountRUB = math.Round(z.AmountRUB*amount/z.Amount*100) / 100
+	}
+
+	if _, _, err := r.releaseHoldTx(ctx, tx, z.HoldID, models.HoldStatusCaptured); err != nil {
+		return nil, err
+	}
+
+	transferID, err := r.settleTx(ctx, tx, z.PayerAccountID, z.PayeeAccountID, amountRUB, z.Currency, models.TransferDetails{Memo: z.Memo})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE authorizations
+        SET status = $1, captured_amount = $2, transfer_id = $3, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $4
+    `, models.AuthorizationStatusCaptured, amount, transferID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetAuthorization(ctx, id)
+}
+
+// Void снимает удержание без списания
+func (r *Repository) Void(ctx context.Context, id, payee uuid.UUID) (*models.Authorization, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	z, err := r.lockAuthorizationTx(ctx, tx, id, payee)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.finishAuthorizationTx(ctx, tx, z, models.HoldStatusReleased, models.AuthorizationStatusVoided); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetAuthorization(ctx, id)
+}
+
+func (r *Repository) finishAuthorizationTx(ctx context.Context, tx *sql.Tx, z *models.Authorization, holdStatus, status string) error {
+	if _, _, err := r.releaseHoldTx(ctx, tx, z.HoldID, holdStatus); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+        UPDATE authorizations SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+    `, status, z.ID)
+	return err
+}
+
+// ExpireAuthorizations снимает просроченные удержания авторизаций
+func (r *Repository) ExpireAuthorizations(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, authorizationSelect+`
+        WHERE z.status = $1 AND z.expires_at <= CURRENT_TIMESTAMP
+        ORDER BY z.expires_at
+        LIMIT 100
+        FOR UPDATE OF z SKIP LOCKED
+    `, models.AuthorizationStatusAuthorized)
+	if err != nil {
+		return 0, err
+	}
+
+	var expired []*models.Authorization
+	for rows.Next() {
+		z, err := scanAuthorization(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		expired = append(expired, z)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, z := range expired {
+		if err := r.finishAuthorizationTx(ctx, tx, z, models.HoldStatusExpired, models.AuthorizationStatusExpired); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrLimitExceeded — сумма исходящих переводов за день превысила бы лимит
+var ErrLimitExceeded = errors.New("daily transfer limit exceeded")
+
+// limitExceededError несет данные отклоненной попытки до места, где внешняя транзакция
+// уже откачена и событие limit.exceeded можно записать
+type limitExceededError struct {
+	event models.LimitExceededEventData
+}
+
+func (e *limitExceededError) Error() string { return ErrLimitExceeded.Error() }
+
+func (e *limitExceededError) Unwrap() error { return ErrLimitExceeded }
+
+// SetDailyTransferLimit задает лимит исходящих переводов счета за сутки в RUB; 0 — без лимита.
+// Вызывается при старте, до обработки запросов.
+func (r *Repository) SetDailyTransferLimit(limit float64) {
+	r.dailyLimit = limit
+}
+
+// checkDailyLimitTx проверяет лимит списаний по инициативе клиента, когда строка счета
+// отправителя уже заблокирована: параллельные списания не могут вместе обойти лимит.
+// В расход входят переводы дня с counts_toward_limit и удержания дня (сделки, авторизации),
+// которые еще действуют или уже списаны.
+func (r *Repository) checkDailyLimitTx(ctx context.Context, tx *sql.Tx, from uuid.UUID, amount float64) error {
+	if r.dailyLimit <= 0 {
+		return nil
+	}
+
+	var used float64
+	err := tx.QueryRowContext(ctx, `
+        SELECT
+            (SELECT COALESCE(SUM(amount), 0)
+             FROM transfers
+             WHERE from_account_id = $1 AND counts_toward_limit
+               AND created_at >= date_trunc('day', CURRENT_TIMESTAMP))
+          + (SELECT COALESCE(SUM(amount), 0)
+             FROM holds
+             WHERE account_id = $1 AND status IN ($2, $3)
+               AND created_at >= date_trunc('day', CURRENT_TIMESTAMP))
+    `, from, models.HoldStatusActive, models.HoldStatusCaptured).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used+amount <= r.dailyLimit {
+		return nil
+	}
+
+	return &limitExceededError{event: models.LimitExceededEventData{
+		AccountID: from,
+		Amount:    amount,
+		Used:      used,
+		Limit:     r.dailyLimit,
+		CreatedAt: time.Now(),
+	}}
+}
+
+// rollbackOnLimit откатывает tx, если err — отказ по лимиту, и только после этого записывает
+// событие limit.exceeded: блокировка счета и соединение уже освобождены. Возвращает err как есть.
+func (r *Repository) rollbackOnLimit(ctx context.Context, tx *sql.Tx, err error) error {
+	var limitErr *limitExceededError
+	if !errors.As(err, &limitErr) {
+		return err
+	}
+	tx.Rollback()
+	if recErr := r.recordLimitExceeded(ctx, limitErr.event); recErr != nil {
+		slog.ErrorContext(ctx, "failed to record limit breach", "error", recErr)
+	}
+	return err
+}
+
+func (r *Repository) recordLimitExceeded(ctx context.Context, event models.LimitExceededEventData) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.addOutboxEventTx(ctx, tx, models.AggregateAccount, event.AccountID, models.EventLimitExceeded, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,443 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrCheckoutSessionState — действие недопустимо в текущем статусе сессии
+var ErrCheckoutSessionState = errors.New("invalid checkout session state")
+
+const merchantSelect = `
+        SELECT m.id, m.user_id, a.id, m.name, m.webhook_endpoint_id,
+               COALESCE(CASE WHEN e.active THEN e.url END, ''), m.created_at
+        FROM merchants m
+        JOIN accounts a ON a.user_id = m.user_id
+        LEFT JOIN webhook_endpoints e ON e.id = m.webhook_endpoint_id`
+
+func scanMerchant(row interface{ Scan(...interface{}) error }) (*models.Merchant, error) {
+	var m models.Merchant
+	err := row.Scan(&m.ID, &m.UserID, &m.AccountID, &m.Name, &m.WebhookEndpointID, &m.WebhookURL, &m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// CreateMerchant создает профиль мерчанта; если указан адрес вебхука, заводит эндпоинт
+// с подпиской на события оплаты
+func (r *Repository) CreateMerchant(ctx context.Context, userID uuid.UUID, name, webhookURL, secret string) (*models.Merchant, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var endpointID *uuid.UUID
+	if webhookURL != "" {
+		id, err := r.createWebhookEndpointTx(ctx, tx, userID, webhookURL, secret, models.CheckoutEvents)
+		if err != nil {
+			return nil, err
+		}
+		endpointID = &id
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO merchants (user_id, name, webhook_endpoint_id)
+        VALUES ($1, $2, $3)
+    `, userID, name, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetMerchantByUserID(ctx, userID)
+}
+
+// UpdateMerchant меняет название и адрес вебхука. Пустой адрес отключает вебхук.
+// Возвращает true, если эндпоинт был создан заново с секретом secret.
+func (r *Repository) UpdateMerchant(ctx context.Context, merchantID uuid.UUID, name, webhookURL, secret string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var (
+		userID     uuid.UUID
+		endpointID *uuid.UUID
+	)
+	err = tx.QueryRowContext(ctx, `
+        SELECT user_id, webhook_endpoint_id FROM merchants WHERE id = $1 FOR UPDATE
+    `, merchantID).Scan(&userID, &endpointID)
+	if err != nil {
+		return false, err
+	}
+
+	created := false
+	switch {
+	case endpointID != nil:
+		_, err = tx.ExecContext(ctx, `
+            UPDATE webhook_endpoints
+            SET url = CASE WHEN $2 = '' THEN url ELSE $2 END, active = $2 <> ''
+            WHERE id = $1
+        `, *endpointID, webhookURL)
+	case webhookURL != "":
+		var id uuid.UUID
+		id, err = r.createWebhookEndpointTx(ctx, tx, userID, webhookURL, secret, models.CheckoutEvents)
+		endpointID = &id
+		created = true
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE merchants SET name = $2, webhook_endpoint_id = $3 WHERE id = $1
+    `, merchantID, name, endpointID)
+	if err != nil {
+		return false, err
+	}
+
+	return created, tx.Commit()
+}
+
+func (r *Repository) GetMerchantByUserID(ctx context.Context, userID uuid.UUID) (*models.Merchant, error) {
+	m, err := scanMerchant(r.db.QueryRowContext(ctx, merchantSelect+`
+        WHERE m.user_id = $1
+    `, userID))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+const checkoutSessionSelect = `
+        SELECT s.id, s.merchant_id, m.name, s.amount, s.currency, COALESCE(s.description, ''), COALESCE(s.reference, ''),
+               CASE WHEN s.status = 'open' AND s.expires_at <= CURRENT_TIMESTAMP THEN 'expired' ELSE s.status END,
+               s.success_url, s.cancel_url, s.payer_account_id, s.transfer_id, s.refunded_amount,
+               s.expires_at, s.created_at, s.paid_at, s.completed_at
+        FROM checkout_sessions s
+        JOIN merchants m ON m.id = s.merchant_id`
+
+func scanCheckoutSession(row interface{ Scan(...interface{}) error }) (*models.CheckoutSession, error) {
+	var s models.CheckoutSession
+	err := row.Scan(&s.ID, &s.MerchantID, &s.MerchantName, &s.Amount, &s.Currency, &s.Description, &s.Reference,
+		&s.Status, &s.SuccessURL, &s.CancelURL, &s.PayerAccountID, &s.TransferID, &s.RefundedAmount,
+		&s.ExpiresAt, &s.CreatedAt, &s.PaidAt, &s.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *Repository) CreateCheckoutSession(ctx context.Context, merchantID uuid.UUID, req models.CreateCheckoutSessionRequest, currency string, expiresAt time.Time) (*models.CheckoutSession, error) {
+	var id uuid.UUID
+	err := r.db.QueryRowContext(ctx, `
+        INSERT INTO checkout_sessions (merchant_id, amount, currency, description, reference, status, success_url, cancel_url, expires_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9)
+        RETURNING id
+    `, merchantID, req.Amount, currency, req.Description, req.Reference, models.CheckoutStatusOpen,
+		req.SuccessURL, req.CancelURL, expiresAt).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetCheckoutSession(ctx, id)
+}
+
+func (r *Repository) GetCheckoutSession(ctx context.Context, id uuid.UUID) (*models.CheckoutSession, error) {
+	s, err := scanCheckoutSession(r.db.QueryRowContext(ctx, checkoutSessionSelect+`
+        WHERE s.id = $1
+    `, id))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *Repository) GetCheckoutSessionsByMerchant(ctx context.Context, merchantID uuid.UUID) ([]models.CheckoutSession, error) {
+	rows, err := r.db.QueryContext(ctx, checkoutSessionSelect+`
+        WHERE s.merchant_id = $1
+        ORDER BY s.created_at DESC
+    `, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.CheckoutSession
+	for rows.Next() {
+		s, err := scanCheckoutSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *s)
+	}
+	return sessions, rows.Err()
+}
+
+// checkoutLock — сессия, заблокированная на время транзакции, со счетом и владельцем мерчанта
+type checkoutLock struct {
+	session           *models.CheckoutSession
+	merchantUserID    uuid.UUID
+	merchantAccountID uuid.UUID
+	amountRUB         float64
+}
+
+func (r *Repository) lockCheckoutSessionTx(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*checkoutLock, error) {
+	var l checkoutLock
+	err := tx.QueryRowContext(ctx, `
+        SELECT m.user_id, a.id, COALESCE(s.amount_rub, 0)
+        FROM checkout_sessions s
+        JOIN merchants m ON m.id = s.merchant_id
+        JOIN accounts a ON a.user_id = m.user_id
+        WHERE s.id = $1
+        FOR UPDATE OF s
+    `, id).Scan(&l.merchantUserID, &l.merchantAccountID, &l.amountRUB)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("checkout session not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l.session, err = scanCheckoutSession(tx.QueryRowContext(ctx, checkoutSessionSelect+`
+        WHERE s.id = $1
+    `, id))
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// finishCheckoutTx перечитывает сессию, ставит событие в очередь вебхуков и фиксирует транзакцию
+func (r *Repository) finishCheckoutTx(ctx context.Context, tx *sql.Tx, l *checkoutLock, eventType string, data func(*models.CheckoutSession) interface{}) (*models.CheckoutSession, error) {
+	s, err := scanCheckoutSession(tx.QueryRowContext(ctx, checkoutSessionSelect+`
+        WHERE s.id = $1
+    `, l.session.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.enqueueWebhookTx(ctx, tx, l.merchantUserID, eventType, data(s)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func sessionData(s *models.CheckoutSession) interface{} { return s }
+
+// PayCheckoutSession списывает сумму сессии со счета плательщика в пользу мерчанта
+func (r *Repository) PayCheckoutSession(ctx context.Context, id, payerAccountID uuid.UUID, amountRUB float64) (*models.CheckoutSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	l, err := r.lockCheckoutSessionTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if l.session.Status != models.CheckoutStatusOpen {
+		return nil, fmt.Errorf("%w: session is %s", ErrCheckoutSessionState, l.session.Status)
+	}
+	if l.merchantAccountID == payerAccountID {
+		return nil, fmt.Errorf("cannot pay your own checkout session")
+	}
+
+	details := models.TransferDetails{Memo: l.session.Description, Reference: l.session.Reference}
+	transferID, err := r.transferTx(ctx, tx, payerAccountID, l.merchantAccountID, amountRUB, l.session.Currency, details)
+	if err != nil {
+		return nil, r.rollbackOnLimit(ctx, tx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE checkout_sessions
+        SET status = $2, payer_account_id = $3, transfer_id = $4, amount_rub = $5, paid_at = CURRENT_TIMESTAMP
+        WHERE id = $1
+    `, id, models.CheckoutStatusPaid, payerAccountID, transferID, amountRUB)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.finishCheckoutTx(ctx, tx, l, models.EventCheckoutPaid, sessionData)
+}
+
+// CompleteCheckoutSession — мерчант подтверждает, что заказ по оплаченной сессии выполнен
+func (r *Repository) CompleteCheckoutSession(ctx context.Context, merchantID, id uuid.UUID) (*models.CheckoutSession, error) {
+	return r.setCheckoutStatus(ctx, merchantID, id, models.CheckoutStatusPaid, models.CheckoutStatusCompleted, models.EventCheckoutCompleted)
+}
+
+// CancelCheckoutSession отменяет неоплаченную сессию. merchantID равен uuid.Nil,
+// когда отмену инициирует плательщик с токеном из checkout_url.
+func (r *Repository) CancelCheckoutSession(ctx context.Context, merchantID, id uuid.UUID) (*models.CheckoutSession, error) {
+	return r.setCheckoutStatus(ctx, merchantID, id, models.CheckoutStatusOpen, models.CheckoutStatusCancelled, models.EventCheckoutCancelled)
+}
+
+func (r *Repository) setCheckoutStatus(ctx context.Context, merchantID, id uuid.UUID, from, to, eventType string) (*models.CheckoutSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	l, err := r.lockCheckoutSessionTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if merchantID != uuid.Nil && l.session.MerchantID != merchantID {
+		return nil, fmt.Errorf("checkout session not found")
+	}
+	if l.session.Status != from {
+		return nil, fmt.Errorf("%w: session is %s", ErrCheckoutSessionState, l.session.Status)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE checkout_sessions
+        SET status = $2, completed_at = CASE WHEN $2 = $3 THEN CURRENT_TIMESTAMP ELSE completed_at END
+        WHERE id = $1
+    `, id, to, models.CheckoutStatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.finishCheckoutTx(ctx, tx, l, eventType, sessionData)
+}
+
+// RefundCheckoutSession возвращает плательщику всю сумму или ее часть. Сумма в рублях
+// считается пропорционально списанной при оплате, чтобы изменение курса не влияло на возврат.
+func (r *Repository) RefundCheckoutSession(ctx context.Context, merchantID, id uuid.UUID, amount float64, reason string) (*models.Refund, *models.CheckoutSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	l, err := r.lockCheckoutSessionTx(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := l.session
+	if s.MerchantID != merchantID {
+		return nil, nil, fmt.Errorf("checkout session not found")
+	}
+	if s.Status != models.CheckoutStatusPaid && s.Status != models.CheckoutStatusCompleted {
+		return nil, nil, fmt.Errorf("%w: session is %s", ErrCheckoutSessionState, s.Status)
+	}
+
+	remaining := math.Round((s.Amount-s.RefundedAmount)*100) / 100
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount <= 0 || amount > remaining {
+		return nil, nil, fmt.Errorf("refund amount must be between 0.01 and %.2f", remaining)
+	}
+
+	refundRUB := math.Round(l.amountRUB*amount/s.Amount*100) / 100
+	if amount == remaining {
+		// Последний возврат забирает остаток целиком, без ошибок округления
+		var refundedRUB float64
+		err = tx.QueryRowContext(ctx, `
+            SELECT COALESCE(SUM(amount_rub), 0) FROM checkout_refunds WHERE session_id = $1
+        `, id).Scan(&refundedRUB)
+		if err != nil {
+			return nil, nil, err
+		}
+		refundRUB = math.Round((l.amountRUB-refundedRUB)*100) / 100
+	}
+
+	details := models.TransferDetails{Memo: reason, Reference: s.Reference}
+	transferID, err := r.settleTx(ctx, tx, l.merchantAccountID, *s.PayerAccountID, refundRUB, s.Currency, details)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var refund models.Refund
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO checkout_refunds (session_id, amount, amount_rub, currency, reason, transfer_id)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+        RETURNING id, session_id, amount, currency, COALESCE(reason, ''), transfer_id, created_at
+    `, id, amount, refundRUB, s.Currency, reason, transferID).Scan(
+		&refund.ID, &refund.SessionID, &refund.Amount, &refund.Currency, &refund.Reason, &refund.TransferID, &refund.CreatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE checkout_sessions
+        SET refunded_amount = refunded_amount + $2,
+            status = CASE WHEN refunded_amount + $2 >= amount THEN $3 ELSE status END
+        WHERE id = $1
+    `, id, amount, models.CheckoutStatusRefunded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated, err := r.finishCheckoutTx(ctx, tx, l, models.EventCheckoutRefunded, func(s *models.CheckoutSession) interface{} {
+		return map[string]interface{}{"session": s, "refund": refund}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &refund, updated, nil
+}
+
+func (r *Repository) GetCheckoutRefunds(ctx context.Context, sessionID uuid.UUID) ([]models.Refund, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, session_id, amount, currency, COALESCE(reason, ''), transfer_id, created_at
+        FROM checkout_refunds
+        WHERE session_id = $1
+        ORDER BY created_at
+    `, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []models.Refund
+	for rows.Next() {
+		var f models.Refund
+		if err := rows.Scan(&f.ID, &f.SessionID, &f.Amount, &f.Currency, &f.Reason, &f.TransferID, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, f)
+	}
+	return refunds, rows.Err()
+}
+
+func (r *Repository) GetMerchant(ctx context.Context, id uuid.UUID) (*models.Merchant, error) {
+	m, err := scanMerchant(r.db.QueryRowContext(ctx, merchantSelect+`
+        WHERE m.id = $1
+    `, id))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// GetNotificationPreferences возвращает nil, если пользователь еще не менял настройки
+func (r *Repository) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	var (
+		p        models.NotificationPreferences
+		channels []byte
+	)
+	err := r.db.QueryRowContext(ctx, `
+        SELECT language, channels FROM notification_preferences WHERE user_id = $1
+    `, userID).Scan(&p.Language, &channels)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(channels, &p.Channels); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *Repository) SaveNotificationPreferences(ctx context.Context, userID uuid.UUID, p models.NotificationPreferences) error {
+	channels, err := json.Marshal(p.Channels)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+        INSERT INTO notification_preferences (user_id, language, channels)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id) DO UPDATE
+        SET language = EXCLUDED.language, channels = EXCLUDED.channels, updated_at = CURRENT_TIMESTAMP
+    `, userID, p.Language, string(channels))
+	return err
+}
+
+// NotificationSent проверяет, доставлено ли уже уведомление о событии по каналу:
+// события приходят как минимум однократно, повторно отправлять нельзя
+func (r *Repository) NotificationSent(ctx context.Context, eventID, userID uuid.UUID, channel string) (bool, error) {
+	var sent bool
+	err := r.db.QueryRowContext(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM notifications
+            WHERE event_id = $1 AND user_id = $2 AND channel = $3 AND status = 'sent'
+        )
+    `, eventID, userID, channel).Scan(&sent)
+	return sent, err
+}
+
+// SaveNotification записывает результат доставки. Повторная попытка по тому же событию
+// и каналу обновляет существующую запись.
+func (r *Repository) SaveNotification(ctx context.Context, n *models.Notification) error {
+	return r.db.QueryRowContext(ctx, `
+        INSERT INTO notifications (user_id, event_id, kind, channel, recipient, subject, body, status, error, sent_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+        ON CONFLICT (event_id, user_id, channel) DO UPDATE
+        SET recipient = EXCLUDED.recipient, subject = EXCLUDED.subject, body = EXCLUDED.body,
+            status = EXCLUDED.status, error = EXCLUDED.error, sent_at = EXCLUDED.sent_at
+        RETURNING id, created_at
+    `, n.UserID, n.EventID, n.Kind, n.Channel, n.Recipient, n.Subject, n.Body, n.Status, n.Error, n.SentAt).Scan(&n.ID, &n.CreatedAt)
+}
+
+func (r *Repository) GetNotifications(ctx context.Context, userID uuid.UUID, limit int) ([]models.Notification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, user_id, event_id, kind, channel, recipient, subject, body, status, COALESCE(error, ''), created_at, sent_at
+        FROM notifications
+        WHERE user_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2
+    `, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.EventID, &n.Kind, &n.Channel, &n.Recipient, &n.Subject, &n.Body,
+			&n.Status, &n.Error, &n.CreatedAt, &n.SentAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
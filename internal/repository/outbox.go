@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// outboxRelayLock — ключ advisory-блокировки: публикует только один экземпляр сервиса,
+// поэтому события уходят строго в порядке Sequence
+const outboxRelayLock = 7245001
+
+// addOutboxEventTx записывает доменное событие в той же транзакции, что и изменение данных
+func (r *Repository) addOutboxEventTx(ctx context.Context, tx *sql.Tx, aggregateType string, aggregateID uuid.UUID, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO outbox_events (event_id, aggregate_type, aggregate_id, event_type, payload)
+        VALUES ($1, $2, $3, $4, $5)
+    `, uuid.New(), aggregateType, aggregateID, eventType, string(payload))
+	return err
+}
+
+const outboxEventColumns = "id, sequence, event_id, event_type, aggregate_type, aggregate_id, payload, created_at"
+
+// scanOutboxEvent возвращает также внутренний id строки: до публикации Sequence еще не присвоен
+func scanOutboxEvent(row interface{ Scan(...interface{}) error }) (int64, *models.DomainEvent, error) {
+	var (
+		e        models.DomainEvent
+		id       int64
+		sequence sql.NullInt64
+		payload  []byte
+	)
+	if err := row.Scan(&id, &sequence, &e.ID, &e.Type, &e.AggregateType, &e.AggregateID, &payload, &e.CreatedAt); err != nil {
+		return 0, nil, err
+	}
+	e.Sequence = sequence.Int64
+	e.Payload = payload
+	return id, &e, nil
+}
+
+// ProcessOutbox передает неопубликованные события в publish по порядку записи. Каждому событию
+// при публикации присваивается Sequence — порядковый номер в потоке, по которому потребители
+// отслеживают смещение. На первой ошибке обработка останавливается, остаток уйдет в следующий
+// раз — доставка как минимум однократная.
+func (r *Repository) ProcessOutbox(ctx context.Context, limit int, publish func(models.DomainEvent) error) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxRelayLock).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+        SELECT `+outboxEventColumns+`
+        FROM outbox_events
+        WHERE published_at IS NULL
+        ORDER BY id
+        LIMIT $1
+    `, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		events []models.DomainEvent
+		ids    []int64
+	)
+	for rows.Next() {
+		id, e, err := scanOutboxEvent(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, *e)
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var publishErr error
+	for i, e := range events {
+		err = tx.QueryRowContext(ctx, `
+            UPDATE outbox_events
+            SET sequence = nextval('outbox_event_sequence'), published_at = CURRENT_TIMESTAMP
+            WHERE id = $1
+            RETURNING sequence
+        `, ids[i]).Scan(&e.Sequence)
+		if err != nil {
+			return 0, err
+		}
+
+		if publishErr = publish(e); publishErr != nil {
+			_, err = tx.ExecContext(ctx, "UPDATE outbox_events SET sequence = NULL, published_at = NULL WHERE id = $1", ids[i])
+			if err != nil {
+				return 0, err
+			}
+			break
+		}
+		published++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// GetPublishedEvents читает опубликованные события после смещения afterSequence
+func (r *Repository) GetPublishedEvents(ctx context.Context, afterSequence int64, limit int) ([]models.DomainEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+outboxEventColumns+`
+        FROM outbox_events
+        WHERE sequence > $1
+        ORDER BY sequence
+        LIMIT $2
+    `, afterSequence, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.DomainEvent
+	for rows.Next() {
+		_, e, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
+
+// GetConsumerOffset возвращает Sequence последнего обработанного потребителем события
+func (r *Repository) GetConsumerOffset(ctx context.Context, consumer string) (int64, error) {
+	var offset int64
+	err := r.db.QueryRowContext(ctx, "SELECT last_sequence FROM event_consumer_offsets WHERE consumer = $1", consumer).Scan(&offset)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return offset, err
+}
+
+func (r *Repository) SaveConsumerOffset(ctx context.Context, consumer string, sequence int64) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO event_consumer_offsets (consumer, last_sequence, updated_at)
+        VALUES ($1, $2, CURRENT_TIMESTAMP)
+        ON CONFLICT (consumer) DO UPDATE
+        SET last_sequence = GREATEST(event_consumer_offsets.last_sequence, EXCLUDED.last_sequence),
+            updated_at = CURRENT_TIMESTAMP
+    `, consumer, sequence)
+	return err
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"math/big"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrPaymentLinkInactive — ссылка оплачена (одноразовая), отменена или истекла
+var ErrPaymentLinkInactive = errors.New("payment link is no longer active")
+
+const paymentLinkCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+const paymentLinkColumns = `
+        id, code, account_id, amount, currency, COALESCE(memo, ''), single_use,
+        CASE WHEN status = 'active' AND expires_at <= CURRENT_TIMESTAMP THEN 'expired' ELSE status END,
+        payments_count, expires_at, created_at`
+
+func scanPaymentLink(row interface{ Scan(...interface{}) error }) (*models.PaymentLink, error) {
+	var (
+		l      models.PaymentLink
+		amount sql.NullFloat64
+	)
+	err := row.Scan(&l.ID, &l.Code, &l.AccountID, &amount, &l.Currency, &l.Memo, &l.SingleUse,
+		&l.Status, &l.PaymentsCount, &l.ExpiresAt, &l.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Valid {
+		l.Amount = &amount.Float64
+	}
+	return &l, nil
+}
+
+// generatePaymentLinkCode — короткий код для URL без похожих символов (0/o, 1/l/i)
+func generatePaymentLinkCode() (string, error) {
+	code := make([]byte, 10)
+	max := big.NewInt(int64(len(paymentLinkCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = paymentLinkCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func (r *Repository) CreatePaymentLink(ctx context.Context, accountID uuid.UUID, amount *float64, currency, memo string, singleUse bool, expiresAt *time.Time) (*models.PaymentLink, error) {
+	for attempt := 0; ; attempt++ {
+		code, err := generatePaymentLinkCode()
+		if err != nil {
+			return nil, err
+		}
+
+		l, err := scanPaymentLink(r.db.QueryRowContext(ctx, `
+            INSERT INTO payment_links (code, account_id, amount, currency, memo, single_use, status, expires_at)
+            VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+            RETURNING `+paymentLinkColumns,
+			code, accountID, amount, currency, memo, singleUse, models.PaymentLinkStatusActive, expiresAt))
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "payment_links_code_key" && attempt < 5 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return l, nil
+	}
+}
+
+func (r *Repository) GetPaymentLinkByCode(ctx context.Context, code string) (*models.PaymentLink, error) {
+	l, err := scanPaymentLink(r.db.QueryRowContext(ctx, `
+        SELECT `+paymentLinkColumns+`
+        FROM payment_links
+        WHERE code = $1
+    `, code))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (r *Repository) GetPaymentLinksByAccount(ctx context.Context, accountID uuid.UUID) ([]models.PaymentLink, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+paymentLinkColumns+`
+        FROM payment_links
+        WHERE account_id = $1
+        ORDER BY created_at DESC
+    `, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.PaymentLink{}
+	for rows.Next() {
+		l, err := scanPaymentLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *l)
+	}
+	return links, rows.Err()
+}
+
+func (r *Repository) GetPaymentLinkPayments(ctx context.Context, linkID uuid.UUID) ([]models.PaymentLinkPayment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, link_id, payer_account_id, transfer_id, amount, currency, created_at
+        FROM payment_link_payments
+        WHERE link_id = $1
+        ORDER BY created_at DESC
+    `, linkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []models.PaymentLinkPayment{}
+	for rows.Next() {
+		var p models.PaymentLinkPayment
+		if err := rows.Scan(&p.ID, &p.LinkID, &p.PayerAccountID, &p.TransferID, &p.Amount, &p.Currency, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+// CancelPaymentLink отменяет активную ссылку владельца. Возвращает false, если отменять нечего.
+func (r *Repository) CancelPaymentLink(ctx context.Context, accountID, id uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+        UPDATE payment_links SET status = $1
+        WHERE id = $2 AND account_id = $3 AND status = $4
+    `, models.PaymentLinkStatusCancelled, id, accountID, models.PaymentLinkStatusActive)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// PayPaymentLink проводит перевод по ссылке. Ссылка блокируется на время транзакции,
+// поэтому одноразовую ссылку нельзя оплатить дважды.
+func (r *Repository) PayPaymentLink(ctx context.Context, linkID, from uuid.UUID, amountRUB, amount float64, currency string, details models.TransferDetails) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		to     uuid.UUID
+		active bool
+	)
+	err = tx.QueryRowContext(ctx, `
+        SELECT account_id,
+               status = $2 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+        FROM payment_links
+        WHERE id = $1
+        FOR UPDATE
+    `, linkID, models.PaymentLinkStatusActive).Scan(&to, &active)
+	if err == sql.ErrNoRows || (err == nil && !active) {
+		return uuid.Nil, ErrPaymentLinkInactive
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	transferID, err := r.transferTx(ctx, tx, from, to, amountRUB, currency, details)
+	if err != nil {
+		return uuid.Nil, r.rollbackOnLimit(ctx, tx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO payment_link_payments (link_id, payer_account_id, transfer_id, amount, currency)
+        VALUES ($1, $2, $3, $4, $5)
+    `, linkID, from, transferID, amount, currency)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE payment_links
+        SET payments_count = payments_count + 1,
+            status = CASE WHEN single_use THEN $2 ELSE status END
+        WHERE id = $1
+    `, linkID, models.PaymentLinkStatusUsed)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return transferID, nil
+}
@@ -3,16 +3,23 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
+	"money-transfer-service/internal/accountnumber"
 	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/tracing"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type Repository struct {
 	db *sql.DB
+
+	// dailyLimit — лимит исходящих переводов за сутки в RUB, 0 — без лимита
+	dailyLimit float64
 }
 
 func (r *Repository) CreateTransfer(ctx context.Context, from, to uuid.UUID, amount float64, currency string) error {
@@ -27,7 +34,8 @@ func NewRepository(db *sql.DB) *Repository {
 	return &Repository{db: db}
 }
 func (r *Repository) DepositMoney(ctx context.Context, accountID uuid.UUID, amount float64) error {
-	log.Printf("Attempting to deposit %.2f to account %s", amount, accountID.String())
+	ctx, span := tracing.Start(ctx, "Repository.DepositMoney")
+	defer span.End()
 
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -35,49 +43,47 @@ func (r *Repository) DepositMoney(ctx context.Context, accountID uuid.UUID, amou
 	}
 	defer tx.Rollback()
 
-	// Проверим существование счета
-	var exists bool
-	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM accounts WHERE id = $1)", accountID).Scan(&exists)
+	// Проверим существование и статус счета
+	var status string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM accounts WHERE id = $1 FOR UPDATE", accountID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return ErrAccountNotFound
+	}
 	if err != nil {
-		log.Printf("Error checking account existence: %v", err)
 		return err
 	}
 
-	if !exists {
-		return fmt.Errorf("account not found")
+	if err := checkCreditAllowed(status); err != nil {
+		return err
 	}
 
 	// Выполним пополнение
-	result, err := tx.ExecContext(ctx, `
+	event := models.DepositEventData{AccountID: accountID, Amount: amount}
+	err = tx.QueryRowContext(ctx, `
         UPDATE accounts 
         SET balance = balance + $1 
         WHERE id = $2
-    `, amount, accountID)
+        RETURNING balance
+    `, amount, accountID).Scan(&event.Balance)
 
+	if err == sql.ErrNoRows {
+		return ErrAccountNotFound
+	}
 	if err != nil {
-		log.Printf("Deposit error: %v", err)
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		log.Printf("Error getting rows affected: %v", err)
+	if err := r.addOutboxEventTx(ctx, tx, models.AggregateAccount, accountID, models.EventDepositCompleted, event); err != nil {
 		return err
 	}
-
-	log.Printf("Rows affected: %d", rowsAffected)
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("account not found")
+	if err := r.enqueueAccountWebhookTx(ctx, tx, accountID, models.EventDepositCompleted, event); err != nil {
+		return err
 	}
 
 	// Зафиксируем транзакцию
 	if err := tx.Commit(); err != nil {
-		log.Printf("Error committing transaction: %v", err)
 		return err
 	}
-
-	log.Printf("Deposit successful")
 	return nil
 }
 
@@ -85,9 +91,9 @@ func (r *Repository) DepositMoney(ctx context.Context, accountID uuid.UUID, amou
 func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	err := r.db.QueryRowContext(ctx, `
-        SELECT id, email, password_hash, full_name, created_at 
+        SELECT id, email, password_hash, full_name, role, created_at 
         FROM users WHERE email = $1
-    `, email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.CreatedAt)
+    `, email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.Role, &user.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -101,9 +107,9 @@ func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*models.
 func (r *Repository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
 	err := r.db.QueryRowContext(ctx, `
-        SELECT id, email, password_hash, full_name, created_at 
+        SELECT id, email, password_hash, full_name, role, created_at 
         FROM users WHERE id = $1
-    `, id).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.CreatedAt)
+    `, id).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.Role, &user.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -119,8 +125,8 @@ func (r *Repository) CreateUser(ctx context.Context, email, passwordHash, fullNa
 	err := r.db.QueryRowContext(ctx, `
         INSERT INTO users (email, password_hash, full_name) 
         VALUES ($1, $2, $3)
-        RETURNING id, email, password_hash, full_name, created_at
-    `, email, passwordHash, fullName).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.CreatedAt)
+        RETURNING id, email, password_hash, full_name, role, created_at
+    `, email, passwordHash, fullName).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.Role, &user.CreatedAt)
 
 	if err != nil {
 		return nil, err
@@ -128,26 +134,38 @@ func (r *Repository) CreateUser(ctx context.Context, email, passwordHash, fullNa
 	return &user, nil
 }
 
+// CreateAccount создает счет с новым номером; при редком совпадении номера генерирует другой
 func (r *Repository) CreateAccount(ctx context.Context, userID uuid.UUID) (*models.Account, error) {
-	var account models.Account
-	err := r.db.QueryRowContext(ctx, `
-        INSERT INTO accounts (user_id, balance) 
-        VALUES ($1, $2)
-        RETURNING id, user_id, balance
-    `, userID, 0.00).Scan(&account.ID, &account.UserID, &account.Balance)
+	for attempt := 0; ; attempt++ {
+		number, err := accountnumber.Generate()
+		if err != nil {
+			return nil, err
+		}
 
-	if err != nil {
-		return nil, err
+		var account models.Account
+		err = r.db.QueryRowContext(ctx, `
+            INSERT INTO accounts (user_id, balance, account_number) 
+            VALUES ($1, $2, $3)
+            RETURNING id, user_id, account_number, balance, held_balance, status
+        `, userID, 0.00, number).Scan(&account.ID, &account.UserID, &account.Number, &account.Balance, &account.HeldBalance, &account.Status)
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "accounts_account_number_key" && attempt < 5 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &account, nil
 	}
-	return &account, nil
 }
 func (r *Repository) GetAccountByUserID(ctx context.Context, userID uuid.UUID) (*models.Account, error) {
 	var account models.Account
 	err := r.db.QueryRowContext(ctx, `
-        SELECT id, user_id, balance 
+        SELECT id, user_id, account_number, balance, held_balance, status
         FROM accounts 
         WHERE user_id = $1
-    `, userID).Scan(&account.ID, &account.UserID, &account.Balance)
+    `, userID).Scan(&account.ID, &account.UserID, &account.Number, &account.Balance, &account.HeldBalance, &account.Status)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -161,11 +179,11 @@ func (r *Repository) GetAccountByUserID(ctx context.Context, userID uuid.UUID) (
 func (r *Repository) GetAccountByEmail(ctx context.Context, email string) (*models.Account, error) {
 	var account models.Account
 	err := r.db.QueryRowContext(ctx, `
-        SELECT a.id, a.user_id, a.balance 
+        SELECT a.id, a.user_id, a.account_number, a.balance, a.held_balance, a.status
         FROM accounts a
         JOIN users u ON a.user_id = u.id
         WHERE u.email = $1
-    `, email).Scan(&account.ID, &account.UserID, &account.Balance)
+    `, email).Scan(&account.ID, &account.UserID, &account.Number, &account.Balance, &account.HeldBalance, &account.Status)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -176,20 +194,79 @@ func (r *Repository) GetAccountByEmail(ctx context.Context, email string) (*mode
 	return &account, nil
 }
 
-func (r *Repository) GetTransfersByAccount(ctx context.Context, accountID uuid.UUID) ([]models.Transfer, error) {
-	query := `
-        SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.currency, t.created_at,
+func (r *Repository) GetAccountByNumber(ctx context.Context, number string) (*models.Account, error) {
+	var account models.Account
+	err := r.db.QueryRowContext(ctx, `
+        SELECT id, user_id, account_number, balance, held_balance, status
+        FROM accounts
+        WHERE account_number = $1
+    `, number).Scan(&account.ID, &account.UserID, &account.Number, &account.Balance, &account.HeldBalance, &account.Status)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// Теги возвращаются только те, что поставил владелец accountID
+const transferSelect = `
+        SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.currency,
+               COALESCE(t.memo, '') AS memo, COALESCE(t.reference, '') AS reference,
+               COALESCE(tg.tags, '{}') AS tags, t.created_at,
                u1.email as from_email, u2.email as to_email
         FROM transfers t
         LEFT JOIN accounts a1 ON t.from_account_id = a1.id
         LEFT JOIN users u1 ON a1.user_id = u1.id
         LEFT JOIN accounts a2 ON t.to_account_id = a2.id
         LEFT JOIN users u2 ON a2.user_id = u2.id
-        WHERE t.from_account_id = $1 OR t.to_account_id = $1
+        LEFT JOIN LATERAL (
+            SELECT array_agg(tt.tag ORDER BY tt.tag) AS tags
+            FROM transfer_tags tt
+            WHERE tt.transfer_id = t.id AND tt.account_id = $1
+        ) tg ON TRUE
+        WHERE (t.from_account_id = $1 OR t.to_account_id = $1)`
+
+func (r *Repository) GetTransfersByAccount(ctx context.Context, accountID uuid.UUID) ([]models.Transfer, error) {
+	return r.queryTransfers(ctx, transferSelect+`
         ORDER BY t.created_at DESC
-    `
+    `, accountID)
+}
+
+// SearchTransfers ищет по назначению, референсу, личным тегам и имени/email контрагента.
+// Каждый источник сопоставляется с запросом отдельно: назначение и референс — через
+// проиндексированную колонку search_vector, теги и контрагенты — в пределах счета.
+func (r *Repository) SearchTransfers(ctx context.Context, accountID uuid.UUID, query string, limit int) ([]models.Transfer, error) {
+	return r.queryTransfers(ctx, `
+        WITH matched AS (
+            SELECT t.id FROM transfers t
+            WHERE t.search_vector @@ websearch_to_tsquery('simple', $2)
+              AND (t.from_account_id = $1 OR t.to_account_id = $1)
+            UNION
+            SELECT tt.transfer_id FROM transfer_tags tt
+            WHERE tt.account_id = $1 AND to_tsvector('simple', tt.tag) @@ websearch_to_tsquery('simple', $2)
+            UNION
+            SELECT t.id FROM transfers t
+            JOIN accounts ca ON ca.id = CASE WHEN t.from_account_id = $1 THEN t.to_account_id ELSE t.from_account_id END
+            JOIN users cp ON cp.id = ca.user_id
+            WHERE (t.from_account_id = $1 OR t.to_account_id = $1)
+              AND to_tsvector('simple', cp.full_name || ' ' || cp.email) @@ websearch_to_tsquery('simple', $2)
+        ), found AS (`+transferSelect+`
+              AND t.id IN (SELECT id FROM matched)
+        )
+        SELECT f.id, f.from_account_id, f.to_account_id, f.amount, f.currency, f.memo, f.reference, f.tags,
+               f.created_at, f.from_email, f.to_email
+        FROM found f
+        JOIN transfers t ON t.id = f.id
+        ORDER BY ts_rank(t.search_vector, websearch_to_tsquery('simple', $2)) DESC, f.created_at DESC
+        LIMIT $3
+    `, accountID, query, limit)
+}
 
-	rows, err := r.db.QueryContext(ctx, query, accountID)
+func (r *Repository) queryTransfers(ctx context.Context, query string, args ...interface{}) ([]models.Transfer, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -204,6 +281,9 @@ func (r *Repository) GetTransfersByAccount(ctx context.Context, accountID uuid.U
 			&t.To,
 			&t.Amount,
 			&t.Currency,
+			&t.Memo,
+			&t.Reference,
+			pq.Array(&t.Tags),
 			&t.CreatedAt,
 			&t.FromEmail,
 			&t.ToEmail,
@@ -213,70 +293,181 @@ func (r *Repository) GetTransfersByAccount(ctx context.Context, accountID uuid.U
 		}
 		transfers = append(transfers, t)
 	}
-	return transfers, nil
+	return transfers, rows.Err()
+}
+
+// SetTransferTags заменяет набор личных тегов владельца счета на переводе
+func (r *Repository) SetTransferTags(ctx context.Context, accountID, transferID uuid.UUID, tags []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `
+        SELECT EXISTS(SELECT 1 FROM transfers WHERE id = $1 AND (from_account_id = $2 OR to_account_id = $2))
+    `, transferID, accountID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("transfer not found")
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM transfer_tags WHERE transfer_id = $1 AND account_id = $2", transferID, accountID)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO transfer_tags (transfer_id, account_id, tag)
+            VALUES ($1, $2, $3)
+            ON CONFLICT DO NOTHING
+        `, transferID, accountID, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // Остальные методы...
 
-func (r *Repository) GetBalance(ctx context.Context, id uuid.UUID) (float64, error) {
-	// Реализация будет в postgres.go
-	return 0, nil
+func (r *Repository) GetBalance(ctx context.Context, id uuid.UUID) (*models.Balance, error) {
+	balance := models.Balance{AccountID: id, Currency: "RUB"}
+	err := r.db.QueryRowContext(ctx, `
+        SELECT account_number, balance, held_balance FROM accounts WHERE id = $1
+    `, id).Scan(&balance.AccountNumber, &balance.Ledger, &balance.Held)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	balance.Available = balance.Ledger - balance.Held
+	return &balance, nil
 }
 
-func (r *Repository) TransferMoney(ctx context.Context, from, to uuid.UUID, amount float64, currency string) error {
-	log.Printf("Transfer attempt: from=%s, to=%s, amount=%.2f, currency=%s", from, to, amount, currency)
+func (r *Repository) TransferMoney(ctx context.Context, from, to uuid.UUID, amount float64, currency string, details models.TransferDetails) (uuid.UUID, error) {
+	ctx, span := tracing.Start(ctx, "Repository.TransferMoney")
+	defer span.End()
 
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		log.Printf("Begin transaction error: %v", err)
-		return err
+		return uuid.Nil, err
 	}
 	defer tx.Rollback()
 
-	// Проверяем баланс отправителя в RUB
-	var currentBalance float64
-	err = tx.QueryRowContext(ctx, "SELECT balance FROM accounts WHERE id = $1 FOR UPDATE", from).Scan(&currentBalance)
+	transferID, err := r.transferTx(ctx, tx, from, to, amount, currency, details)
 	if err != nil {
-		log.Printf("Balance check error: %v", err)
-		return err
+		return uuid.Nil, r.rollbackOnLimit(ctx, tx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
 	}
 
-	log.Printf("Current balance: %.2f, Transfer amount: %.2f", currentBalance, amount)
+	// Суммы и счета в лог не пишем: детали перевода есть в базе по transfer_id
+	slog.DebugContext(ctx, "transfer committed", "transfer_id", transferID)
+	return transferID, nil
+}
+
+// transferTx списывает, зачисляет и записывает перевод по инициативе клиента внутри уже
+// открытой транзакции. Списание проверяется по дневному лимиту и учитывается в нем.
+func (r *Repository) transferTx(ctx context.Context, tx *sql.Tx, from, to uuid.UUID, amount float64, currency string, details models.TransferDetails) (uuid.UUID, error) {
+	return r.moveFundsTx(ctx, tx, from, to, amount, currency, details, true)
+}
+
+// settleTx проводит расчет по уже удержанным или уже полученным средствам: захват
+// авторизации, выпуск сделки, возврат мерчанта, перенос остатка при закрытии счета.
+// Дневной лимит к таким переводам не применяется.
+func (r *Repository) settleTx(ctx context.Context, tx *sql.Tx, from, to uuid.UUID, amount float64, currency string, details models.TransferDetails) (uuid.UUID, error) {
+	return r.moveFundsTx(ctx, tx, from, to, amount, currency, details, false)
+}
+
+func (r *Repository) moveFundsTx(ctx context.Context, tx *sql.Tx, from, to uuid.UUID, amount float64, currency string, details models.TransferDetails, userDebit bool) (uuid.UUID, error) {
+	// Проверяем статус и доступный баланс отправителя в RUB (без учета удержаний)
+	var (
+		currentBalance float64
+		status         string
+	)
+	err := tx.QueryRowContext(ctx, "SELECT balance - held_balance, status FROM accounts WHERE id = $1 FOR UPDATE", from).Scan(&currentBalance, &status)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, fmt.Errorf("sender %w", ErrAccountNotFound)
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := checkDebitAllowed(status); err != nil {
+		return uuid.Nil, err
+	}
+
+	if userDebit {
+		if err := r.checkDailyLimitTx(ctx, tx, from, amount); err != nil {
+			return uuid.Nil, err
+		}
+	}
 
 	if currentBalance < amount {
-		log.Printf("Insufficient funds: have %.2f, need %.2f", currentBalance, amount)
-		return fmt.Errorf("insufficient funds")
+		return uuid.Nil, ErrInsufficientFunds
 	}
 
 	// Списание средств
 	_, err = tx.ExecContext(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2", amount, from)
 	if err != nil {
-		log.Printf("Debit error: %v", err)
-		return err
+		return uuid.Nil, err
 	}
 
 	// Зачисление средств
+	err = tx.QueryRowContext(ctx, "SELECT status FROM accounts WHERE id = $1 FOR UPDATE", to).Scan(&status)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, fmt.Errorf("recipient %w", ErrAccountNotFound)
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := checkCreditAllowed(status); err != nil {
+		return uuid.Nil, err
+	}
+
 	_, err = tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + $1 WHERE id = $2", amount, to)
 	if err != nil {
-		log.Printf("Credit error: %v", err)
-		return err
+		return uuid.Nil, err
 	}
 
 	// Запись о переводе
-	_, err = tx.ExecContext(ctx, `
-        INSERT INTO transfers (from_account_id, to_account_id, amount, currency)
-        VALUES ($1, $2, $3, $4)
-    `, from, to, amount, currency)
+	event := models.TransferEventData{
+		FromAccountID: from,
+		ToAccountID:   to,
+		Amount:        amount,
+		Currency:      currency,
+		Memo:          details.Memo,
+		Reference:     details.Reference,
+	}
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO transfers (from_account_id, to_account_id, amount, currency, memo, reference, counts_toward_limit)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+        RETURNING id, created_at
+    `, from, to, amount, currency, details.Memo, details.Reference, userDebit).Scan(&event.TransferID, &event.CreatedAt)
 	if err != nil {
-		log.Printf("Transfer record error: %v", err)
-		return err
+		return uuid.Nil, err
 	}
 
-	if err := tx.Commit(); err != nil {
-		log.Printf("Commit error: %v", err)
-		return err
+	if err := r.addOutboxEventTx(ctx, tx, models.AggregateTransfer, event.TransferID, models.EventTransferCompleted, event); err != nil {
+		return uuid.Nil, err
+	}
+	if err := r.enqueueAccountWebhookTx(ctx, tx, from, models.EventTransferCompleted, event); err != nil {
+		return uuid.Nil, err
+	}
+	if err := r.enqueueAccountWebhookTx(ctx, tx, to, models.EventTransferReceived, event); err != nil {
+		return uuid.Nil, err
 	}
 
-	log.Printf("Transfer completed successfully")
-	return nil
+	return event.TransferID, nil
 }
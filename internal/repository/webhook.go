@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const webhookDeliveryColumns = `
+        d.id, d.endpoint_id, d.event_type, d.payload, d.status, d.attempts, d.redelivery_of, d.next_attempt_at,
+        d.last_status_code, COALESCE(d.last_error, ''), d.created_at, d.delivered_at`
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*models.WebhookDelivery, error) {
+	var (
+		d          models.WebhookDelivery
+		payload    []byte
+		statusCode sql.NullInt64
+	)
+	dest := append([]interface{}{&d.ID, &d.EndpointID, &d.EventType, &payload, &d.Status, &d.Attempts, &d.RedeliveryOf, &d.NextAttemptAt,
+		&statusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		d.LastStatusCode = &code
+	}
+	return &d, nil
+}
+
+const webhookEndpointColumns = "id, user_id, url, events, active, created_at"
+
+func scanWebhookEndpoint(row interface{ Scan(...interface{}) error }) (*models.WebhookEndpoint, error) {
+	var e models.WebhookEndpoint
+	if err := row.Scan(&e.ID, &e.UserID, &e.URL, pq.Array(&e.Events), &e.Active, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *Repository) createWebhookEndpointTx(ctx context.Context, tx *sql.Tx, userID uuid.UUID, url, secret string, events []string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `
+        INSERT INTO webhook_endpoints (user_id, url, secret, events)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `, userID, url, secret, pq.Array(events)).Scan(&id)
+	return id, err
+}
+
+// enqueueWebhookTx ставит событие в очередь доставки на все активные эндпоинты пользователя,
+// подписанные на этот тип. Запись идет в той же транзакции, что и само изменение,
+// поэтому событие не теряется и не отправляется для откатившейся операции.
+func (r *Repository) enqueueWebhookTx(ctx context.Context, tx *sql.Tx, userID uuid.UUID, eventType string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(models.WebhookEvent{
+		ID:        uuid.New(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO webhook_deliveries (endpoint_id, event_type, payload, status, next_attempt_at)
+        SELECT id, $2, $3, $4, CURRENT_TIMESTAMP
+        FROM webhook_endpoints
+        WHERE user_id = $1 AND active AND $2 = ANY(events)
+    `, userID, eventType, string(payload), models.WebhookDeliveryPending)
+	return err
+}
+
+// enqueueAccountWebhookTx — то же для владельца счета
+func (r *Repository) enqueueAccountWebhookTx(ctx context.Context, tx *sql.Tx, accountID uuid.UUID, eventType string, data interface{}) error {
+	var userID uuid.UUID
+	if err := tx.QueryRowContext(ctx, "SELECT user_id FROM accounts WHERE id = $1", accountID).Scan(&userID); err != nil {
+		return err
+	}
+	return r.enqueueWebhookTx(ctx, tx, userID, eventType, data)
+}
+
+func (r *Repository) CreateWebhookEndpoint(ctx context.Context, userID uuid.UUID, url, secret string, events []string) (*models.WebhookEndpoint, error) {
+	e, err := scanWebhookEndpoint(r.db.QueryRowContext(ctx, `
+        INSERT INTO webhook_endpoints (user_id, url, secret, events)
+        VALUES ($1, $2, $3, $4)
+        RETURNING `+webhookEndpointColumns,
+		userID, url, secret, pq.Array(events)))
+	if err != nil {
+		return nil, err
+	}
+	e.Secret = secret
+	return e, nil
+}
+
+func (r *Repository) GetWebhookEndpoints(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+webhookEndpointColumns+`
+        FROM webhook_endpoints
+        WHERE user_id = $1
+        ORDER BY created_at
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []models.WebhookEndpoint
+	for rows.Next() {
+		e, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, *e)
+	}
+	return endpoints, rows.Err()
+}
+
+func (r *Repository) GetWebhookEndpoint(ctx context.Context, userID, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	e, err := scanWebhookEndpoint(r.db.QueryRowContext(ctx, `
+        SELECT `+webhookEndpointColumns+`
+        FROM webhook_endpoints
+        WHERE id = $1 AND user_id = $2
+    `, id, userID))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// UpdateWebhookEndpoint сохраняет эндпоинт целиком; частичное обновление собирается в сервисе
+func (r *Repository) UpdateWebhookEndpoint(ctx context.Context, e *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	updated, err := scanWebhookEndpoint(r.db.QueryRowContext(ctx, `
+        UPDATE webhook_endpoints
+        SET url = $3, events = $4, active = $5
+        WHERE id = $1 AND user_id = $2
+        RETURNING `+webhookEndpointColumns,
+		e.ID, e.UserID, e.URL, pq.Array(e.Events), e.Active))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *Repository) RotateWebhookSecret(ctx context.Context, userID, id uuid.UUID, secret string) (*models.WebhookEndpoint, error) {
+	e, err := scanWebhookEndpoint(r.db.QueryRowContext(ctx, `
+        UPDATE webhook_endpoints SET secret = $3
+        WHERE id = $1 AND user_id = $2
+        RETURNING `+webhookEndpointColumns,
+		id, userID, secret))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.Secret = secret
+	return e, nil
+}
+
+func (r *Repository) DeleteWebhookEndpoint(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhook_endpoints WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// GetWebhookDeliveries — журнал доставок эндпоинта, новые сверху. Пустой status — все доставки.
+func (r *Repository) GetWebhookDeliveries(ctx context.Context, endpointID uuid.UUID, status string, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+webhookDeliveryColumns+`
+        FROM webhook_deliveries d
+        WHERE d.endpoint_id = $1 AND ($2 = '' OR d.status = $2)
+        ORDER BY d.created_at DESC
+        LIMIT $3
+    `, endpointID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Redeliver ставит копию доставки в очередь; исходная запись остается в журнале как есть
+func (r *Repository) Redeliver(ctx context.Context, endpointID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	d, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, `
+        INSERT INTO webhook_deliveries AS d (endpoint_id, event_type, payload, status, next_attempt_at, redelivery_of)
+        SELECT endpoint_id, event_type, payload, $3, CURRENT_TIMESTAMP, id
+        FROM webhook_deliveries
+        WHERE id = $1 AND endpoint_id = $2
+        RETURNING `+webhookDeliveryColumns,
+		deliveryID, endpointID, models.WebhookDeliveryPending))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// EnqueueTestWebhook отправляет на эндпоинт тестовое событие независимо от подписки
+func (r *Repository) EnqueueTestWebhook(ctx context.Context, endpointID uuid.UUID) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(models.WebhookEvent{
+		ID:        uuid.New(),
+		Type:      models.EventWebhookTest,
+		CreatedAt: time.Now().UTC(),
+		Data:      json.RawMessage(`{}`),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWebhookDelivery(r.db.QueryRowContext(ctx, `
+        INSERT INTO webhook_deliveries AS d (endpoint_id, event_type, payload, status, next_attempt_at)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+        RETURNING `+webhookDeliveryColumns,
+		endpointID, models.EventWebhookTest, string(payload), models.WebhookDeliveryPending))
+}
+
+// ClaimWebhookDeliveries забирает пачку доставок, время которых пришло, и откладывает их
+// на lease, чтобы параллельный воркер не отправил то же событие повторно
+func (r *Repository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDispatch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        UPDATE webhook_deliveries d
+        SET next_attempt_at = CURRENT_TIMESTAMP + $3::float8 * INTERVAL '1 second'
+        FROM webhook_endpoints e
+        WHERE e.id = d.endpoint_id AND d.id IN (
+            SELECT id FROM webhook_deliveries
+            WHERE status = $1 AND next_attempt_at <= CURRENT_TIMESTAMP
+            ORDER BY next_attempt_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING `+webhookDeliveryColumns+`, e.url, e.secret
+    `, models.WebhookDeliveryPending, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dispatches []models.WebhookDispatch
+	for rows.Next() {
+		var url, secret string
+		d, err := scanWebhookDelivery(rows, &url, &secret)
+		if err != nil {
+			return nil, err
+		}
+		dispatches = append(dispatches, models.WebhookDispatch{Delivery: *d, URL: url, Secret: secret})
+	}
+	return dispatches, rows.Err()
+}
+
+// RecordWebhookAttempt сохраняет результат попытки. Без nextAttemptAt неуспешная доставка
+// считается окончательно проваленной.
+func (r *Repository) RecordWebhookAttempt(ctx context.Context, id uuid.UUID, statusCode *int, lastError string, delivered bool, nextAttemptAt *time.Time) error {
+	status := models.WebhookDeliveryPending
+	switch {
+	case delivered:
+		status = models.WebhookDeliveryDelivered
+		nextAttemptAt = nil
+	case nextAttemptAt == nil:
+		status = models.WebhookDeliveryFailed
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE webhook_deliveries
+        SET attempts = attempts + 1, status = $2, last_status_code = $3, last_error = NULLIF($4, ''),
+            next_attempt_at = $5, delivered_at = CASE WHEN $6 THEN CURRENT_TIMESTAMP ELSE delivered_at END
+        WHERE id = $1
+    `, id, status, statusCode, lastError, nextAttemptAt, delivered)
+	return err
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var accountStatuses = map[string]bool{
+	models.AccountStatusActive:        true,
+	models.AccountStatusFrozen:        true,
+	models.AccountStatusDebitBlocked:  true,
+	models.AccountStatusCreditBlocked: true,
+	models.AccountStatusClosed:        true,
+}
+
+func (s *Service) ownAccount(ctx context.Context, userID uuid.UUID) (*models.Account, error) {
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+func normalizeReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", fmt.Errorf("reason is required")
+	}
+	return reason, nil
+}
+
+// FreezeOwnAccount позволяет владельцу заморозить свой счет, например при потере телефона
+func (s *Service) FreezeOwnAccount(ctx context.Context, userID uuid.UUID, reason string) (*models.Account, error) {
+	reason, err := normalizeReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.ownAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != models.AccountStatusActive {
+		return nil, fmt.Errorf("only an active account can be frozen, current status: %s", account.Status)
+	}
+
+	if err := s.repo.SetAccountStatus(ctx, account.ID, models.AccountStatusFrozen, userID, reason); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAccountByID(ctx, account.ID)
+}
+
+// UnfreezeOwnAccount снимает заморозку, только если ее поставил сам владелец
+func (s *Service) UnfreezeOwnAccount(ctx context.Context, userID uuid.UUID, reason string) (*models.Account, error) {
+	reason, err := normalizeReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.ownAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != models.AccountStatusFrozen {
+		return nil, fmt.Errorf("account is not frozen")
+	}
+
+	history, err := s.repo.GetAccountStatusHistory(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 || history[0].ChangedBy != userID {
+		return nil, fmt.Errorf("account was frozen by support and cannot be unfrozen by the owner")
+	}
+
+	if err := s.repo.SetAccountStatus(ctx, account.ID, models.AccountStatusActive, userID, reason); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAccountByID(ctx, account.ID)
+}
+
+// CloseOwnAccount закрывает счет владельца, остаток при необходимости переводится на другой счет
+func (s *Service) CloseOwnAccount(ctx context.Context, userID uuid.UUID, req models.CloseAccountRequest) (*models.Account, error) {
+	reason, err := normalizeReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Закрытие счета всегда требует подтверждения паролем
+	if err := s.verifyPassword(ctx, userID, req.Password); err != nil {
+		return nil, err
+	}
+
+	account, err := s.ownAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sweepTo *uuid.UUID
+	if req.SweepToEmail != "" {
+		target, err := s.repo.GetAccountByEmail(ctx, req.SweepToEmail)
+		if err != nil {
+			return nil, err
+		}
+		if target == nil {
+			return nil, fmt.Errorf("sweep %w for email: %s", repository.ErrAccountNotFound, req.SweepToEmail)
+		}
+		if target.ID == account.ID {
+			return nil, fmt.Errorf("cannot sweep balance to the account being closed")
+		}
+		sweepTo = &target.ID
+	}
+
+	if err := s.repo.CloseAccount(ctx, account.ID, sweepTo, userID, reason); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAccountByID(ctx, account.ID)
+}
+
+func (s *Service) GetOwnAccountStatusHistory(ctx context.Context, userID uuid.UUID) ([]models.AccountStatusChange, error) {
+	account, err := s.ownAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetAccountStatusHistory(ctx, account.ID)
+}
+
+// SetAccountStatus — смена статуса любого счета администратором
+func (s *Service) SetAccountStatus(ctx context.Context, adminID, accountID uuid.UUID, req models.SetAccountStatusRequest) (*models.Account, error) {
+	if !accountStatuses[req.Status] {
+		return nil, fmt.Errorf("unknown account status: %s", req.Status)
+	}
+
+	reason, err := normalizeReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.repo.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+
+	if err := s.repo.SetAccountStatus(ctx, accountID, req.Status, adminID, reason); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAccountByID(ctx, accountID)
+}
+
+func (s *Service) GetAccount(ctx context.Context, accountID uuid.UUID) (*models.Account, error) {
+	return s.repo.GetAccountByID(ctx, accountID)
+}
+
+func (s *Service) GetAccountStatusHistory(ctx context.Context, accountID uuid.UUID) ([]models.AccountStatusChange, error) {
+	return s.repo.GetAccountStatusHistory(ctx, accountID)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"money-transfer-service/internal/alias"
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+	"money-transfer-service/internal/tracing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const aliasCodeTTL = 10 * time.Minute
+
+// aliasCodeLimits защищают коды подтверждения от перебора, а номера — от потока SMS
+var aliasCodeLimits = repository.AliasCodeLimits{
+	Cooldown:     time.Minute,
+	PerValue:     5,
+	PerUser:      10,
+	MaxAttempts:  5,
+	LockDuration: 24 * time.Hour,
+}
+
+var (
+	ErrAliasNotFound = errors.New("alias not found")
+	// ErrPhoneVerificationUnavailable — не настроен SMS-шлюз для кодов подтверждения
+	ErrPhoneVerificationUnavailable = errors.New("phone verification is not available")
+)
+
+func (s *Service) GetAliases(ctx context.Context, userID uuid.UUID) ([]models.Alias, error) {
+	return s.repo.GetAliasesByUser(ctx, userID)
+}
+
+// CreateAlias регистрирует псевдоним. Телефон подтверждается кодом, имя пользователя
+// закрепляется сразу, если оно свободно.
+func (s *Service) CreateAlias(ctx context.Context, userID uuid.UUID, req models.CreateAliasRequest) (*models.Alias, error) {
+	value, err := s.aliases.Normalize(req.Type, req.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Type {
+	case models.AliasUsername:
+		return s.repo.ClaimAlias(ctx, userID, req.Type, value)
+	case models.AliasPhone:
+		if s.codeSender == nil {
+			return nil, ErrPhoneVerificationUnavailable
+		}
+		code, err := alias.GenerateCode()
+		if err != nil {
+			return nil, err
+		}
+
+		a, err := s.repo.CreateAlias(ctx, userID, req.Type, value, alias.HashCode(code), time.Now().Add(aliasCodeTTL), aliasCodeLimits)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.codeSender.SendCode(ctx, req.Type, value, code); err != nil {
+			return nil, fmt.Errorf("failed to send verification code: %w", err)
+		}
+		return a, nil
+	default:
+		return nil, fmt.Errorf("alias type %s cannot be registered", req.Type)
+	}
+}
+
+func (s *Service) VerifyAlias(ctx context.Context, userID, id uuid.UUID, code string) (*models.Alias, error) {
+	if code == "" {
+		return nil, fmt.Errorf("verification code is required")
+	}
+	return s.repo.VerifyAlias(ctx, userID, id, alias.HashCode(code), aliasCodeLimits)
+}
+
+func (s *Service) DeleteAlias(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.repo.DeleteAlias(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAliasNotFound
+	}
+	return nil
+}
+
+// TransferToRecipient переводит деньги получателю, найденному через справочник псевдонимов
+func (s *Service) TransferToRecipient(ctx context.Context, fromUserID uuid.UUID, recipient models.Recipient, amount float64, currency string, details models.TransferDetails, password string) (transferID uuid.UUID, err error) {
+	ctx, span := tracing.Start(ctx, "Service.TransferToRecipient", attribute.String("recipient.type", recipient.Type))
+	defer func() { tracing.End(span, err) }()
+	defer func() { s.observeTransfer(currency, amount, err) }()
+
+	toAccount, err := s.aliases.Resolve(ctx, recipient)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return s.transferFromUser(ctx, fromUserID, toAccount.ID, amount, currency, details, password)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"money-transfer-service/internal/auth"
+	"money-transfer-service/internal/models"
+)
+
+var (
+	ErrUserExists         = errors.New("user already exists")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// LoginDevice — откуда выполнен вход. Новое устройство порождает уведомление владельцу.
+type LoginDevice struct {
+	Fingerprint string
+	UserAgent   string
+	IP          string
+}
+
+// Register создает пользователя со счетом и выдает JWT
+func (s *Service) Register(ctx context.Context, req models.RegisterRequest, device LoginDevice) (*models.AuthResponse, error) {
+	existing, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, fmt.Errorf("check user existence: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrUserExists
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+	user, err := s.repo.CreateUser(ctx, req.Email, hash, req.FullName)
+	if err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+	if _, err := s.repo.CreateAccount(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("create account: %w", err)
+	}
+
+	return s.issueToken(ctx, user, device)
+}
+
+func (s *Service) Login(ctx context.Context, req models.LoginRequest, device LoginDevice) (*models.AuthResponse, error) {
+	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+	if user == nil || !auth.CheckPassword(ctx, req.Password, user.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issueToken(ctx, user, device)
+}
+
+// issueToken запоминает устройство входа и выдает JWT. Ошибка записи устройства не мешает входу.
+func (s *Service) issueToken(ctx context.Context, user *models.User, device LoginDevice) (*models.AuthResponse, error) {
+	if err := s.repo.RecordLogin(ctx, user.ID, device.Fingerprint, device.UserAgent, device.IP); err != nil {
+		slog.ErrorContext(ctx, "failed to record login device", "user_id", user.ID, "error", err)
+	}
+
+	token, err := auth.GenerateJWT(*user)
+	if err != nil {
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+	return &models.AuthResponse{Token: token, User: *user}, nil
+}
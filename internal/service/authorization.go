@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAuthorizationTTL = 7 * 24 * time.Hour
+	maxAuthorizationTTL     = 30 * 24 * time.Hour
+)
+
+var ErrAuthorizationNotFound = errors.New("authorization not found")
+
+// Authorize резервирует сумму на счете пользователя в пользу получателя платежа
+func (s *Service) Authorize(ctx context.Context, userID uuid.UUID, req models.AuthorizeRequest) (*models.Authorization, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	expiresAt := req.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(defaultAuthorizationTTL)
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future")
+	}
+	if time.Until(expiresAt) > maxAuthorizationTTL {
+		return nil, fmt.Errorf("authorization can be held for at most %d days", int(maxAuthorizationTTL.Hours()/24))
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = "RUB"
+	}
+
+	payer, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if payer == nil {
+		return nil, fmt.Errorf("payer %w", repository.ErrAccountNotFound)
+	}
+
+	payee, err := s.repo.GetAccountByEmail(ctx, req.PayeeEmail)
+	if err != nil {
+		return nil, err
+	}
+	if payee == nil {
+		return nil, fmt.Errorf("payee %w for email: %s", repository.ErrAccountNotFound, req.PayeeEmail)
+	}
+	if payee.ID == payer.ID {
+		return nil, fmt.Errorf("cannot authorize a payment to your own account")
+	}
+
+	details, err := normalizeTransferDetails(models.TransferDetails{Memo: req.Memo})
+	if err != nil {
+		return nil, err
+	}
+
+	amountRUB, err := s.toRUB(ctx, req.Amount, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkStepUp(ctx, userID, payee.ID, amountRUB, req.Password); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Authorize(ctx, payer.ID, payee.ID, req.Amount, amountRUB, currency, details.Memo, expiresAt)
+}
+
+func (s *Service) GetAuthorizations(ctx context.Context, userID uuid.UUID) ([]models.Authorization, error) {
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+	return s.repo.GetAuthorizationsByAccount(ctx, account.ID)
+}
+
+func (s *Service) GetAuthorization(ctx context.Context, userID, id uuid.UUID) (*models.Authorization, error) {
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := s.repo.GetAuthorization(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if z == nil || account == nil || (z.PayerAccountID != account.ID && z.PayeeAccountID != account.ID) {
+		return nil, ErrAuthorizationNotFound
+	}
+	return z, nil
+}
+
+// Capture и Void доступны только получателю платежа. amount — в валюте авторизации,
+// 0 — вся авторизованная сумма.
+func (s *Service) Capture(ctx context.Context, userID, id uuid.UUID, amount float64) (*models.Authorization, error) {
+	if amount < 0 {
+		return nil, fmt.Errorf("amount must not be negative")
+	}
+
+	payee, err := s.payeeAccount(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Capture(ctx, id, payee, amount)
+}
+
+func (s *Service) Void(ctx context.Context, userID, id uuid.UUID) (*models.Authorization, error) {
+	payee, err := s.payeeAccount(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Void(ctx, id, payee)
+}
+
+func (s *Service) payeeAccount(ctx context.Context, userID, id uuid.UUID) (uuid.UUID, error) {
+	z, err := s.GetAuthorization(ctx, userID, id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if account.ID != z.PayeeAccountID {
+		return uuid.Nil, fmt.Errorf("only the payee can capture or void an authorization")
+	}
+	return account.ID, nil
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const MaxBatchLines = 1000
+
+// batchLease — на сколько обработчик забирает пакет; пакет из MaxBatchLines строк
+// проводится с большим запасом
+const batchLease = 5 * time.Minute
+
+// ErrBatchInvalid означает, что хотя бы одна строка пакета не прошла проверку
+var ErrBatchInvalid = errors.New("batch contains invalid lines")
+
+// SubmitTransferBatch проверяет все строки пакета и сохраняет его в очередь фонового
+// обработчика. При ошибках валидации пакет не создается.
+func (s *Service) SubmitTransferBatch(ctx context.Context, userID uuid.UUID, req models.BatchTransferRequest) (*models.TransferBatch, []models.BatchLineError, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = models.BatchModeAtomic
+	}
+	if mode != models.BatchModeAtomic && mode != models.BatchModeBestEffort {
+		return nil, nil, fmt.Errorf("unknown batch mode: %s", mode)
+	}
+	if len(req.Items) == 0 {
+		return nil, nil, fmt.Errorf("batch is empty")
+	}
+	if len(req.Items) > MaxBatchLines {
+		return nil, nil, fmt.Errorf("batch must contain at most %d lines", MaxBatchLines)
+	}
+
+	fromAccount, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fromAccount == nil {
+		return nil, nil, fmt.Errorf("sender %w", repository.ErrAccountNotFound)
+	}
+
+	items, amounts, lineErrors, stepUp, err := s.validateBatchLines(ctx, fromAccount, req.Items)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(lineErrors) > 0 {
+		return nil, lineErrors, ErrBatchInvalid
+	}
+
+	if mode == models.BatchModeAtomic {
+		var total float64
+		for _, amount := range amounts {
+			total += amount
+		}
+		if total > fromAccount.AvailableBalance() {
+			return nil, nil, fmt.Errorf("%w for batch total %.2f RUB", repository.ErrInsufficientFunds, total)
+		}
+	}
+
+	if stepUp {
+		if err := s.verifyPassword(ctx, userID, req.Password); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	batch, err := s.repo.CreateTransferBatch(ctx, userID, fromAccount.ID, mode, items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Будим обработчик, чтобы не ждать следующего опроса очереди
+	select {
+	case s.batchWake <- struct{}{}:
+	default:
+	}
+
+	return batch, nil, nil
+}
+
+func (s *Service) GetTransferBatch(ctx context.Context, userID, id uuid.UUID) (*models.TransferBatch, error) {
+	return s.repo.GetTransferBatch(ctx, userID, id)
+}
+
+// validateBatchLines проверяет каждую строку и собирает все ошибки сразу.
+// Возвращает подготовленные строки, суммы в RUB и признак необходимости подтверждения паролем.
+func (s *Service) validateBatchLines(ctx context.Context, from *models.Account, lines []models.BatchTransferLine) ([]models.TransferBatchItem, []float64, []models.BatchLineError, bool, error) {
+	var (
+		items      = make([]models.TransferBatchItem, 0, len(lines))
+		amounts    = make([]float64, 0, len(lines))
+		lineErrors []models.BatchLineError
+		stepUp     bool
+		recipients = make(map[string]*models.Account)
+	)
+
+	for i, line := range lines {
+		lineNo := i + 1
+		lineError := func(msg string) {
+			lineErrors = append(lineErrors, models.BatchLineError{LineNo: lineNo, Error: msg})
+		}
+
+		email := strings.TrimSpace(line.ToEmail)
+		if email == "" {
+			lineError("recipient email is required")
+			continue
+		}
+		if line.Amount <= 0 {
+			lineError("amount must be positive")
+			continue
+		}
+
+		currency := strings.ToUpper(strings.TrimSpace(line.Currency))
+		if currency == "" {
+			currency = "RUB"
+		}
+		amountRUB, err := s.toRUB(ctx, line.Amount, currency)
+		if err != nil {
+			lineError(err.Error())
+			continue
+		}
+
+		details, err := normalizeTransferDetails(line.TransferDetails)
+		if err != nil {
+			lineError(err.Error())
+			continue
+		}
+
+		toAccount, seen := recipients[email]
+		if !seen {
+			toAccount, err = s.repo.GetAccountByEmail(ctx, email)
+			if err != nil {
+				return nil, nil, nil, false, err
+			}
+			recipients[email] = toAccount
+		}
+		if toAccount == nil {
+			lineError(fmt.Sprintf("recipient account not found for email: %s", email))
+			continue
+		}
+		if toAccount.ID == from.ID {
+			lineError("cannot transfer to your own account")
+			continue
+		}
+
+		if !stepUp {
+			stepUp, err = s.stepUpRequired(ctx, from.UserID, toAccount.ID, amountRUB)
+			if err != nil {
+				return nil, nil, nil, false, err
+			}
+		}
+
+		items = append(items, models.TransferBatchItem{
+			LineNo:      lineNo,
+			ToEmail:     email,
+			ToAccountID: toAccount.ID,
+			Amount:      line.Amount,
+			Currency:    currency,
+			Memo:        details.Memo,
+			Reference:   details.Reference,
+		})
+		amounts = append(amounts, amountRUB)
+	}
+
+	return items, amounts, lineErrors, stepUp, nil
+}
+
+// RunBatchWorker проводит пакеты переводов: при запуске — оставшиеся от прошлого запуска,
+// затем новые по сигналу из SubmitTransferBatch или раз в interval
+func (s *Service) RunBatchWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.processQueuedBatches(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.batchWake:
+		}
+	}
+}
+
+func (s *Service) processQueuedBatches(ctx context.Context) {
+	for ctx.Err() == nil {
+		batch, err := s.repo.ClaimTransferBatch(ctx, batchLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to claim transfer batch", "error", err)
+			}
+			return
+		}
+		if batch == nil {
+			return
+		}
+		s.processTransferBatch(ctx, batch)
+	}
+}
+
+func (s *Service) processTransferBatch(ctx context.Context, batch *models.TransferBatch) {
+	// После перезапуска часть строк уже может быть проведена
+	var items []models.TransferBatchItem
+	for _, item := range batch.Items {
+		if item.Status == models.BatchItemPending {
+			items = append(items, item)
+		}
+	}
+
+	if batch.Mode == models.BatchModeAtomic {
+		s.executeAtomicBatch(ctx, batch, items)
+	} else {
+		s.executeBestEffortBatch(ctx, batch, items)
+	}
+
+	if ctx.Err() != nil {
+		// Остановка сервиса: оставшиеся строки проведутся после перезапуска
+		if err := s.repo.ReleaseTransferBatch(context.WithoutCancel(ctx), batch.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to release batch", "batch_id", batch.ID, "error", err)
+		}
+		return
+	}
+
+	if err := s.repo.FinishTransferBatch(ctx, batch.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to finish batch", "batch_id", batch.ID, "error", err)
+	}
+}
+
+func (s *Service) executeAtomicBatch(ctx context.Context, batch *models.TransferBatch, items []models.TransferBatchItem) {
+	if len(items) == 0 {
+		return
+	}
+
+	amounts := make([]float64, len(items))
+	for i, item := range items {
+		amount, err := s.toRUB(ctx, item.Amount, item.Currency)
+		if err != nil {
+			s.rollBackAtomicBatch(ctx, batch, items, item.LineNo, err)
+			return
+		}
+		amounts[i] = amount
+	}
+
+	failedLine, err := s.repo.ExecuteTransferBatchAtomic(ctx, batch.ID, batch.AccountID, items, amounts)
+	if err == nil || errors.Is(err, repository.ErrBatchAlreadyExecuted) {
+		return
+	}
+	s.rollBackAtomicBatch(ctx, batch, items, failedLine, err)
+}
+
+// rollBackAtomicBatch записывает результат отката: ошибку строки, на которой пакет
+// остановился, или ошибку всего пакета, если строка ни при чем
+func (s *Service) rollBackAtomicBatch(ctx context.Context, batch *models.TransferBatch, items []models.TransferBatchItem, failedLine int, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	slog.WarnContext(ctx, "atomic batch rolled back", "batch_id", batch.ID, "line", failedLine, "error", err)
+
+	if failedLine == 0 {
+		if err := s.repo.FailTransferBatch(ctx, batch.ID, "batch could not be committed, no transfers were made"); err != nil {
+			slog.ErrorContext(ctx, "failed to save batch result", "batch_id", batch.ID, "error", err)
+		}
+		return
+	}
+
+	for _, item := range items {
+		status, msg := models.BatchItemSkipped, "batch rolled back"
+		if item.LineNo == failedLine {
+			status, msg = models.BatchItemFailed, err.Error()
+		}
+		if err := s.repo.SetTransferBatchItemResult(ctx, batch.ID, item.LineNo, status, nil, msg); err != nil {
+			slog.ErrorContext(ctx, "failed to save batch line result", "batch_id", batch.ID, "line", item.LineNo, "error", err)
+		}
+	}
+}
+
+func (s *Service) executeBestEffortBatch(ctx context.Context, batch *models.TransferBatch, items []models.TransferBatchItem) {
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+
+		amount, err := s.toRUB(ctx, item.Amount, item.Currency)
+		if err == nil {
+			// Успешная строка записывается в той же транзакции, что и перевод
+			_, err = s.repo.ExecuteTransferBatchLine(ctx, batch.ID, batch.AccountID, item, amount)
+		}
+		if err == nil || errors.Is(err, repository.ErrBatchAlreadyExecuted) || ctx.Err() != nil {
+			continue
+		}
+
+		if err := s.repo.SetTransferBatchItemResult(ctx, batch.ID, item.LineNo, models.BatchItemFailed, nil, err.Error()); err != nil {
+			slog.ErrorContext(ctx, "failed to save batch line result", "batch_id", batch.ID, "line", item.LineNo, "error", err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"money-transfer-service/internal/auth"
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrStepUpRequired     = errors.New("password confirmation required for transfers to new recipients")
+	ErrStepUpFailed       = errors.New("invalid password")
+	ErrBeneficiaryMissing = errors.New("beneficiary not found")
+)
+
+func (s *Service) GetBeneficiaries(ctx context.Context, userID uuid.UUID) ([]models.Beneficiary, error) {
+	return s.repo.GetBeneficiariesByUser(ctx, userID)
+}
+
+// CreateBeneficiary сохраняет получателя (или меняет его запись) только с паролем: сохраненным
+// получателям крупные переводы уходят без повторного ввода пароля
+func (s *Service) CreateBeneficiary(ctx context.Context, userID uuid.UUID, req models.CreateBeneficiaryRequest) (*models.Beneficiary, error) {
+	nickname := strings.TrimSpace(req.Nickname)
+	if nickname == "" {
+		return nil, fmt.Errorf("nickname is required")
+	}
+	if err := s.verifyPassword(ctx, userID, req.Password); err != nil {
+		return nil, err
+	}
+
+	account, err := s.repo.GetAccountByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("recipient %w for email: %s", repository.ErrAccountNotFound, req.Email)
+	}
+	if account.UserID == userID {
+		return nil, fmt.Errorf("cannot add your own account as a beneficiary")
+	}
+
+	return s.repo.CreateBeneficiary(ctx, userID, account.ID, nickname, req.IsFavorite)
+}
+
+func (s *Service) UpdateBeneficiary(ctx context.Context, userID, id uuid.UUID, req models.UpdateBeneficiaryRequest) (*models.Beneficiary, error) {
+	if req.Nickname != nil {
+		nickname := strings.TrimSpace(*req.Nickname)
+		if nickname == "" {
+			return nil, fmt.Errorf("nickname must not be empty")
+		}
+		req.Nickname = &nickname
+	}
+
+	b, err := s.repo.UpdateBeneficiary(ctx, userID, id, req.Nickname, req.IsFavorite)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, ErrBeneficiaryMissing
+	}
+	return b, nil
+}
+
+func (s *Service) DeleteBeneficiary(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.repo.DeleteBeneficiary(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBeneficiaryMissing
+	}
+	return nil
+}
+
+// ConfirmRecipient возвращает замаскированное имя получателя, чтобы отправитель
+// мог убедиться, что не ошибся в адресе
+func (s *Service) ConfirmRecipient(ctx context.Context, fromUserID uuid.UUID, recipient models.Recipient, amount float64, currency string) (*models.RecipientConfirmation, error) {
+	value, err := s.aliases.Normalize(recipient.Type, recipient.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.aliases.Resolve(ctx, recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.repo.GetUserByID(ctx, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, fmt.Errorf("recipient %w for %s: %s", repository.ErrAccountNotFound, recipient.Type, value)
+	}
+
+	confirmation := &models.RecipientConfirmation{
+		Type:       recipient.Type,
+		Value:      value,
+		MaskedName: MaskFullName(owner.FullName),
+	}
+
+	b, err := s.repo.GetBeneficiaryByAccount(ctx, fromUserID, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if b != nil {
+		confirmation.BeneficiaryID = &b.ID
+		if b.PasswordConfirmed {
+			return confirmation, nil
+		}
+	}
+
+	amountRUB, err := s.toRUB(ctx, amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	confirmation.StepUpRequired = amountRUB >= s.limits.StepUpThreshold
+	return confirmation, nil
+}
+
+// checkStepUp проверяет пароль отправителя для крупных переводов получателям,
+// которых нет в сохраненных с паролем контактах
+func (s *Service) checkStepUp(ctx context.Context, fromUserID, toAccountID uuid.UUID, amountRUB float64, password string) error {
+	required, err := s.stepUpRequired(ctx, fromUserID, toAccountID, amountRUB)
+	if err != nil || !required {
+		return err
+	}
+	return s.verifyPassword(ctx, fromUserID, password)
+}
+
+func (s *Service) stepUpRequired(ctx context.Context, fromUserID, toAccountID uuid.UUID, amountRUB float64) (bool, error) {
+	if amountRUB < s.limits.StepUpThreshold {
+		return false, nil
+	}
+
+	b, err := s.repo.GetBeneficiaryByAccount(ctx, fromUserID, toAccountID)
+	if err != nil {
+		return false, err
+	}
+	return b == nil || !b.PasswordConfirmed, nil
+}
+
+func (s *Service) verifyPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	if password == "" {
+		return ErrStepUpRequired
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || !auth.CheckPassword(ctx, password, user.PasswordHash) {
+		return ErrStepUpFailed
+	}
+	return nil
+}
+
+// MaskFullName оставляет имя и первую букву фамилии: "Alice Smith" -> "Alice S."
+func MaskFullName(fullName string) string {
+	parts := strings.Fields(fullName)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	masked := []string{parts[0]}
+	for _, part := range parts[1:] {
+		r, _ := utf8.DecodeRuneInString(part)
+		masked = append(masked, string(r)+".")
+	}
+	return strings.Join(masked, " ")
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const maxEscrowDuration = 90 * 24 * time.Hour
+
+var ErrEscrowNotFound = errors.New("escrow not found")
+
+func (s *Service) CreateEscrow(ctx context.Context, userID uuid.UUID, req models.CreateEscrowRequest) (*models.Escrow, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if !req.Deadline.After(time.Now()) {
+		return nil, fmt.Errorf("deadline must be in the future")
+	}
+	if time.Until(req.Deadline) > maxEscrowDuration {
+		return nil, fmt.Errorf("deadline must be within %d days", int(maxEscrowDuration.Hours()/24))
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = "RUB"
+	}
+
+	fromAccount, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount == nil {
+		return nil, fmt.Errorf("sender %w", repository.ErrAccountNotFound)
+	}
+
+	toAccount, err := s.repo.GetAccountByEmail(ctx, req.ToEmail)
+	if err != nil {
+		return nil, err
+	}
+	if toAccount == nil {
+		return nil, fmt.Errorf("recipient %w for email: %s", repository.ErrAccountNotFound, req.ToEmail)
+	}
+	if toAccount.ID == fromAccount.ID {
+		return nil, fmt.Errorf("cannot create escrow to your own account")
+	}
+
+	details, err := normalizeTransferDetails(models.TransferDetails{Memo: req.Memo})
+	if err != nil {
+		return nil, err
+	}
+
+	amountRUB, err := s.toRUB(ctx, req.Amount, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkStepUp(ctx, userID, toAccount.ID, amountRUB, req.Password); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateEscrow(ctx, fromAccount.ID, toAccount.ID, req.Amount, amountRUB, currency, details.Memo, req.Deadline)
+}
+
+func (s *Service) GetEscrows(ctx context.Context, userID uuid.UUID) ([]models.Escrow, error) {
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+	return s.repo.GetEscrowsByAccount(ctx, account.ID)
+}
+
+// GetEscrow возвращает сделку и счет пользователя, если он является ее участником
+func (s *Service) GetEscrow(ctx context.Context, userID, id uuid.UUID) (*models.Escrow, *models.Account, error) {
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e, err := s.repo.GetEscrow(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e == nil || account == nil || (e.FromAccountID != account.ID && e.ToAccountID != account.ID) {
+		return nil, nil, ErrEscrowNotFound
+	}
+	return e, account, nil
+}
+
+func (s *Service) ConfirmEscrow(ctx context.Context, userID, id uuid.UUID) (*models.Escrow, error) {
+	_, account, err := s.GetEscrow(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ConfirmEscrow(ctx, id, account.ID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEscrow(ctx, id)
+}
+
+// CancelEscrow доступна отправителю, пока получатель не подтвердил сделку
+func (s *Service) CancelEscrow(ctx context.Context, userID, id uuid.UUID) (*models.Escrow, error) {
+	_, account, err := s.GetEscrow(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RefundEscrow(ctx, id, account.ID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEscrow(ctx, id)
+}
+
+// DisputeEscrow замораживает сделку до решения администратора
+func (s *Service) DisputeEscrow(ctx context.Context, userID, id uuid.UUID) (*models.Escrow, error) {
+	_, account, err := s.GetEscrow(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DisputeEscrow(ctx, id, account.ID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEscrow(ctx, id)
+}
+
+// ResolveEscrowDispute — решение администратора по спорной сделке
+func (s *Service) ResolveEscrowDispute(ctx context.Context, adminID, id uuid.UUID, req models.ResolveEscrowRequest) (*models.Escrow, error) {
+	if req.Outcome != models.EscrowOutcomeRelease && req.Outcome != models.EscrowOutcomeRefund {
+		return nil, fmt.Errorf("unknown escrow outcome: %s", req.Outcome)
+	}
+
+	e, err := s.repo.GetEscrow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, ErrEscrowNotFound
+	}
+
+	if err := s.repo.ResolveEscrowDispute(ctx, id, req.Outcome); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "escrow dispute resolved", "escrow_id", id, "admin_id", adminID, "outcome", req.Outcome)
+	return s.repo.GetEscrow(ctx, id)
+}
+
+// RunHoldWorker периодически завершает сделки с истекшим дедлайном
+// и снимает просроченные удержания авторизаций
+func (s *Service) RunHoldWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.repo.ReleaseExpiredEscrows(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "escrow release failed", "error", err)
+			} else if released > 0 {
+				slog.InfoContext(ctx, "hold worker released escrows", "count", released)
+			}
+
+			expired, err := s.repo.ExpireAuthorizations(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "authorization expiry failed", "error", err)
+			} else if expired > 0 {
+				slog.InfoContext(ctx, "hold worker expired authorizations", "count", expired)
+			}
+		}
+	}
+}
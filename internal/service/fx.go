@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"sort"
+)
+
+// BaseCurrency — валюта счетов; курсы задаются к ней
+const BaseCurrency = "RUB"
+
+// Currencies возвращает поддерживаемые валюты: RUB и валюты из справочника курсов
+func (s *Service) Currencies() []string {
+	currencies := make([]string, 0, len(s.fx.Rates)+1)
+	currencies = append(currencies, BaseCurrency)
+	for currency := range s.fx.Rates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies[1:])
+	return currencies
+}
+
+// ExchangeRate — цена единицы валюты в RUB
+func (s *Service) ExchangeRate(ctx context.Context, currency string) (float64, error) {
+	if currency == BaseCurrency {
+		return 1, nil
+	}
+	return s.getExchangeRate(ctx, currency)
+}
+
+// Convert пересчитывает сумму из одной валюты в другую через RUB.
+// rate — сколько единиц to стоит единица from.
+func (s *Service) Convert(ctx context.Context, amount float64, from, to string) (converted, rate float64, err error) {
+	if amount <= 0 {
+		return 0, 0, ErrInvalidAmount
+	}
+	fromRate, err := s.ExchangeRate(ctx, from)
+	if err != nil {
+		return 0, 0, err
+	}
+	toRate, err := s.ExchangeRate(ctx, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	rate = fromRate / toRate
+	return amount * rate, rate, nil
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrGroupNotFound = errors.New("group not found")
+
+func toKopecks(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromKopecks(kopecks int64) float64 {
+	return float64(kopecks) / 100
+}
+
+func (s *Service) CreateExpenseGroup(ctx context.Context, userID uuid.UUID, req models.CreateGroupRequest) (*models.ExpenseGroup, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("group name is required")
+	}
+
+	memberIDs := []uuid.UUID{userID}
+	for _, email := range req.MemberEmails {
+		member, err := s.repo.GetUserByEmail(ctx, strings.TrimSpace(email))
+		if err != nil {
+			return nil, err
+		}
+		if member == nil {
+			return nil, fmt.Errorf("user not found for email: %s", email)
+		}
+		memberIDs = append(memberIDs, member.ID)
+	}
+
+	return s.repo.CreateExpenseGroup(ctx, name, userID, memberIDs)
+}
+
+func (s *Service) GetExpenseGroups(ctx context.Context, userID uuid.UUID) ([]models.ExpenseGroup, error) {
+	return s.repo.GetExpenseGroupsByUser(ctx, userID)
+}
+
+// GetExpenseGroup возвращает группу только ее участникам
+func (s *Service) GetExpenseGroup(ctx context.Context, userID, groupID uuid.UUID) (*models.ExpenseGroup, error) {
+	member, err := s.repo.IsGroupMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrGroupNotFound
+	}
+
+	group, err := s.repo.GetExpenseGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, ErrGroupNotFound
+	}
+	return group, nil
+}
+
+func (s *Service) AddGroupMember(ctx context.Context, userID, groupID uuid.UUID, email string) (*models.ExpenseGroup, error) {
+	if _, err := s.GetExpenseGroup(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.GetUserByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, fmt.Errorf("user not found for email: %s", email)
+	}
+
+	if err := s.repo.AddGroupMember(ctx, groupID, member.ID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetExpenseGroup(ctx, groupID)
+}
+
+// AddExpense записывает расход, оплаченный текущим пользователем, и делит его между участниками
+func (s *Service) AddExpense(ctx context.Context, userID, groupID uuid.UUID, req models.CreateExpenseRequest) (*models.Expense, error) {
+	group, err := s.GetExpenseGroup(ctx, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, fmt.Errorf("description is required")
+	}
+
+	total := toKopecks(req.Amount)
+	if total <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	shares, err := splitExpense(total, req.SplitType, req.Participants, group.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateExpense(ctx, models.Expense{
+		GroupID:     groupID,
+		PaidBy:      userID,
+		Amount:      fromKopecks(total),
+		Description: description,
+		SplitType:   req.SplitType,
+		Shares:      shares,
+	})
+}
+
+func (s *Service) GetGroupExpenses(ctx context.Context, userID, groupID uuid.UUID) ([]models.Expense, error) {
+	if _, err := s.GetExpenseGroup(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGroupExpenses(ctx, groupID)
+}
+
+func (s *Service) GetGroupBalances(ctx context.Context, userID, groupID uuid.UUID) (*models.GroupBalances, error) {
+	if _, err := s.GetExpenseGroup(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+
+	balances, err := s.repo.GetGroupNetBalances(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GroupBalances{
+		Balances: balances,
+		Debts:    simplifyDebts(balances),
+	}, nil
+}
+
+// SettleUp гасит все долги текущего пользователя в группе минимальным набором переводов
+func (s *Service) SettleUp(ctx context.Context, userID, groupID uuid.UUID, password string) ([]models.GroupSettlement, error) {
+	group, err := s.GetExpenseGroup(ctx, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.repo.GetGroupNetBalances(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var debts []models.GroupDebt
+	for _, debt := range simplifyDebts(balances) {
+		if debt.From == userID {
+			debts = append(debts, debt)
+		}
+	}
+	if len(debts) == 0 {
+		return []models.GroupSettlement{}, nil
+	}
+
+	fromAccount, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount == nil {
+		return nil, fmt.Errorf("sender %w", repository.ErrAccountNotFound)
+	}
+
+	toAccounts := make(map[uuid.UUID]uuid.UUID)
+	stepUp := false
+	for _, debt := range debts {
+		toAccount, err := s.repo.GetAccountByUserID(ctx, debt.To)
+		if err != nil {
+			return nil, err
+		}
+		if toAccount == nil {
+			return nil, fmt.Errorf("recipient %w", repository.ErrAccountNotFound)
+		}
+		toAccounts[debt.To] = toAccount.ID
+
+		if !stepUp {
+			stepUp, err = s.stepUpRequired(ctx, userID, toAccount.ID, debt.Amount)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if stepUp {
+		if err := s.verifyPassword(ctx, userID, password); err != nil {
+			return nil, err
+		}
+	}
+
+	details := models.TransferDetails{Memo: "Расчет в группе «" + group.Name + "»"}
+	settlements := []models.GroupSettlement{}
+	for _, debt := range debts {
+		settlement, err := s.repo.SettleGroupDebt(ctx, groupID, debt, fromAccount.ID, toAccounts[debt.To], details)
+		if err != nil {
+			return settlements, err
+		}
+		settlements = append(settlements, *settlement)
+	}
+	return settlements, nil
+}
+
+// splitExpense делит сумму в копейках между участниками. Остаток от округления
+// распределяется по копейке между первыми участниками.
+func splitExpense(total int64, splitType string, participants []models.ExpenseParticipant, members []models.GroupMember) ([]models.ExpenseShare, error) {
+	isMember := make(map[uuid.UUID]bool)
+	for _, m := range members {
+		isMember[m.UserID] = true
+	}
+
+	if splitType == models.SplitEqual && len(participants) == 0 {
+		for _, m := range members {
+			participants = append(participants, models.ExpenseParticipant{UserID: m.UserID})
+		}
+	}
+	if len(participants) == 0 {
+		return nil, fmt.Errorf("participants are required")
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, p := range participants {
+		if !isMember[p.UserID] {
+			return nil, fmt.Errorf("user %s is not a member of the group", p.UserID)
+		}
+		if seen[p.UserID] {
+			return nil, fmt.Errorf("duplicate participant %s", p.UserID)
+		}
+		seen[p.UserID] = true
+	}
+
+	amounts := make([]int64, len(participants))
+	switch splitType {
+	case models.SplitEqual:
+		n := int64(len(participants))
+		for i := range amounts {
+			amounts[i] = total / n
+		}
+	case models.SplitPercentage:
+		var percentSum float64
+		for i, p := range participants {
+			if p.Percent <= 0 {
+				return nil, fmt.Errorf("percent must be positive")
+			}
+			percentSum += p.Percent
+			amounts[i] = int64(math.Floor(float64(total) * p.Percent / 100))
+		}
+		if math.Abs(percentSum-100) > 1e-6 {
+			return nil, fmt.Errorf("percentages must add up to 100, got %.2f", percentSum)
+		}
+	case models.SplitExact:
+		var sum int64
+		for i, p := range participants {
+			if p.Amount <= 0 {
+				return nil, fmt.Errorf("share amount must be positive")
+			}
+			amounts[i] = toKopecks(p.Amount)
+			sum += amounts[i]
+		}
+		if sum != total {
+			return nil, fmt.Errorf("shares must add up to %.2f, got %.2f", fromKopecks(total), fromKopecks(sum))
+		}
+	default:
+		return nil, fmt.Errorf("unknown split type: %s", splitType)
+	}
+
+	var allocated int64
+	for _, a := range amounts {
+		allocated += a
+	}
+	for i := 0; allocated < total; i = (i + 1) % len(amounts) {
+		amounts[i]++
+		allocated++
+	}
+
+	shares := make([]models.ExpenseShare, len(participants))
+	for i, p := range participants {
+		shares[i] = models.ExpenseShare{UserID: p.UserID, Amount: fromKopecks(amounts[i])}
+	}
+	return shares, nil
+}
+
+// simplifyDebts сводит балансы к небольшому набору переводов: самый крупный
+// должник платит самому крупному кредитору, пока все балансы не обнулятся
+func simplifyDebts(balances []models.GroupBalance) []models.GroupDebt {
+	type party struct {
+		userID  uuid.UUID
+		kopecks int64
+	}
+
+	var creditors, debtors []party
+	for _, b := range balances {
+		net := toKopecks(b.Net)
+		switch {
+		case net > 0:
+			creditors = append(creditors, party{b.UserID, net})
+		case net < 0:
+			debtors = append(debtors, party{b.UserID, -net})
+		}
+	}
+
+	byAmount := func(parties []party) func(i, j int) bool {
+		return func(i, j int) bool { return parties[i].kopecks > parties[j].kopecks }
+	}
+
+	debts := []models.GroupDebt{}
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.SliceStable(creditors, byAmount(creditors))
+		sort.SliceStable(debtors, byAmount(debtors))
+
+		amount := creditors[0].kopecks
+		if debtors[0].kopecks < amount {
+			amount = debtors[0].kopecks
+		}
+		debts = append(debts, models.GroupDebt{
+			From:   debtors[0].userID,
+			To:     creditors[0].userID,
+			Amount: fromKopecks(amount),
+		})
+
+		creditors[0].kopecks -= amount
+		debtors[0].kopecks -= amount
+		if creditors[0].kopecks == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].kopecks == 0 {
+			debtors = debtors[1:]
+		}
+	}
+	return debts
+}
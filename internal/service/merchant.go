@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+	"money-transfer-service/internal/webhook"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultCheckoutTTL = 24 * time.Hour
+	maxCheckoutTTL     = 7 * 24 * time.Hour
+)
+
+var (
+	ErrMerchantNotFound        = errors.New("merchant profile not found")
+	ErrCheckoutSessionNotFound = errors.New("checkout session not found")
+)
+
+// validateCallbackURL допускает только абсолютные http(s)-адреса
+func validateCallbackURL(field, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute http or https URL", field)
+	}
+	return nil
+}
+
+func normalizeMerchantRequest(req models.MerchantRequest) (models.MerchantRequest, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.WebhookURL = strings.TrimSpace(req.WebhookURL)
+	if req.Name == "" || len([]rune(req.Name)) > 100 {
+		return req, fmt.Errorf("merchant name must be 1-100 characters")
+	}
+	if req.WebhookURL != "" {
+		if err := validateWebhookURL("webhook_url", req.WebhookURL); err != nil {
+			return req, err
+		}
+	}
+	return req, nil
+}
+
+// CreateMerchant открывает профиль мерчанта. Секрет вебхука возвращается только здесь.
+func (s *Service) CreateMerchant(ctx context.Context, userID uuid.UUID, req models.MerchantRequest) (*models.Merchant, error) {
+	req, err := normalizeMerchantRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetMerchantByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("merchant profile already exists")
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.repo.CreateMerchant(ctx, userID, req.Name, req.WebhookURL, secret)
+	if err != nil {
+		return nil, err
+	}
+	if req.WebhookURL != "" {
+		m.WebhookSecret = secret
+	}
+	return m, nil
+}
+
+func (s *Service) GetMerchant(ctx context.Context, userID uuid.UUID) (*models.Merchant, error) {
+	m, err := s.repo.GetMerchantByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrMerchantNotFound
+	}
+	return m, nil
+}
+
+func (s *Service) UpdateMerchant(ctx context.Context, userID uuid.UUID, req models.MerchantRequest) (*models.Merchant, error) {
+	req, err := normalizeMerchantRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.GetMerchant(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.UpdateMerchant(ctx, m.ID, req.Name, req.WebhookURL, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err = s.GetMerchant(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		m.WebhookSecret = secret
+	}
+	return m, nil
+}
+
+func (s *Service) CreateCheckoutSession(ctx context.Context, userID uuid.UUID, req models.CreateCheckoutSessionRequest) (*models.CheckoutSession, error) {
+	m, err := s.GetMerchant(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if err := validateCallbackURL("success_url", req.SuccessURL); err != nil {
+		return nil, err
+	}
+	if err := validateCallbackURL("cancel_url", req.CancelURL); err != nil {
+		return nil, err
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = "RUB"
+	}
+	if _, err := s.toRUB(ctx, 1, currency); err != nil {
+		return nil, err
+	}
+
+	details, err := normalizeTransferDetails(models.TransferDetails{Memo: req.Description, Reference: req.Reference})
+	if err != nil {
+		return nil, err
+	}
+	req.Description, req.Reference = details.Memo, details.Reference
+
+	expiresAt := time.Now().Add(defaultCheckoutTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("expiry must be in the future")
+		}
+		if time.Until(*req.ExpiresAt) > maxCheckoutTTL {
+			return nil, fmt.Errorf("expiry must be within %d days", int(maxCheckoutTTL.Hours()/24))
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	return s.repo.CreateCheckoutSession(ctx, m.ID, req, currency, expiresAt)
+}
+
+func (s *Service) GetCheckoutSessions(ctx context.Context, userID uuid.UUID) ([]models.CheckoutSession, error) {
+	m, err := s.GetMerchant(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetCheckoutSessionsByMerchant(ctx, m.ID)
+}
+
+// GetMerchantCheckoutSession возвращает сессию, только если она принадлежит мерчанту пользователя
+func (s *Service) GetMerchantCheckoutSession(ctx context.Context, userID, id uuid.UUID) (*models.CheckoutSession, error) {
+	m, err := s.GetMerchant(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.repo.GetCheckoutSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.MerchantID != m.ID {
+		return nil, ErrCheckoutSessionNotFound
+	}
+	return session, nil
+}
+
+func (s *Service) GetPublicCheckoutSession(ctx context.Context, id uuid.UUID) (*models.PublicCheckoutSession, error) {
+	session, err := s.repo.GetCheckoutSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrCheckoutSessionNotFound
+	}
+
+	return &models.PublicCheckoutSession{
+		ID:           session.ID,
+		MerchantName: session.MerchantName,
+		Amount:       session.Amount,
+		Currency:     session.Currency,
+		Description:  session.Description,
+		Status:       session.Status,
+		ExpiresAt:    session.ExpiresAt,
+	}, nil
+}
+
+// PayCheckoutSession оплачивает сессию со счета пользователя и возвращает адрес,
+// на который нужно вернуть плательщика
+func (s *Service) PayCheckoutSession(ctx context.Context, userID, id uuid.UUID, password string) (*models.CheckoutRedirect, error) {
+	session, err := s.repo.GetCheckoutSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrCheckoutSessionNotFound
+	}
+
+	m, err := s.repo.GetMerchant(ctx, session.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrMerchantNotFound
+	}
+
+	payer, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if payer == nil {
+		return nil, fmt.Errorf("sender %w", repository.ErrAccountNotFound)
+	}
+
+	amountRUB, err := s.toRUB(ctx, session.Amount, session.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkStepUp(ctx, userID, m.AccountID, amountRUB, password); err != nil {
+		return nil, err
+	}
+
+	paid, err := s.repo.PayCheckoutSession(ctx, id, payer.ID, amountRUB)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CheckoutRedirect{Session: paid, RedirectURL: checkoutRedirectURL(paid.SuccessURL, paid.ID)}, nil
+}
+
+// CheckoutToken привязывает отмену к ссылке на оплату: токен есть только в checkout_url,
+// который мерчант отдает плательщику, а не в самом идентификаторе сессии
+func (s *Service) CheckoutToken(id uuid.UUID) string {
+	mac := hmac.New(sha256.New, s.checkoutKey)
+	mac.Write([]byte("checkout:" + id.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deriveCheckoutKey выводит из секрета JWT отдельный ключ: сам секрет подписывает токены
+// доступа и не должен использоваться для других подписей
+func deriveCheckoutKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("checkout-token"))
+	return mac.Sum(nil)
+}
+
+// CancelCheckoutByPayer — плательщик отказался от оплаты и возвращается на cancel_url.
+// Плательщик у открытой сессии еще не известен, поэтому право на отмену подтверждает
+// токен из checkout_url.
+func (s *Service) CancelCheckoutByPayer(ctx context.Context, id uuid.UUID, token string) (*models.CheckoutRedirect, error) {
+	if !hmac.Equal([]byte(token), []byte(s.CheckoutToken(id))) {
+		return nil, ErrCheckoutSessionNotFound
+	}
+
+	session, err := s.repo.CancelCheckoutSession(ctx, uuid.Nil, id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CheckoutRedirect{Session: session, RedirectURL: checkoutRedirectURL(session.CancelURL, session.ID)}, nil
+}
+
+func (s *Service) CancelCheckoutSession(ctx context.Context, userID, id uuid.UUID) (*models.CheckoutSession, error) {
+	session, err := s.GetMerchantCheckoutSession(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CancelCheckoutSession(ctx, session.MerchantID, id)
+}
+
+func (s *Service) CompleteCheckoutSession(ctx context.Context, userID, id uuid.UUID) (*models.CheckoutSession, error) {
+	session, err := s.GetMerchantCheckoutSession(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CompleteCheckoutSession(ctx, session.MerchantID, id)
+}
+
+func (s *Service) RefundCheckoutSession(ctx context.Context, userID, id uuid.UUID, req models.RefundRequest) (*models.Refund, error) {
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("refund amount must not be negative")
+	}
+
+	session, err := s.GetMerchantCheckoutSession(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := normalizeTransferDetails(models.TransferDetails{Memo: req.Reason})
+	if err != nil {
+		return nil, err
+	}
+
+	refund, _, err := s.repo.RefundCheckoutSession(ctx, session.MerchantID, id, req.Amount, details.Memo)
+	return refund, err
+}
+
+func (s *Service) GetCheckoutRefunds(ctx context.Context, userID, id uuid.UUID) ([]models.Refund, error) {
+	if _, err := s.GetMerchantCheckoutSession(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCheckoutRefunds(ctx, id)
+}
+
+func checkoutRedirectURL(base string, sessionID uuid.UUID) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	q := u.Query()
+	q.Set("session_id", sessionID.String())
+	u.RawQuery = q.Encode()
+	return u.String()
+}
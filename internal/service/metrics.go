@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+
+	"money-transfer-service/internal/alias"
+	"money-transfer-service/internal/metrics"
+	"money-transfer-service/internal/repository"
+)
+
+// observeTransfer учитывает перевод в метриках. Валюты вне справочника курсов собираются
+// под меткой other, чтобы произвольный ввод не плодил ряды.
+func (s *Service) observeTransfer(currency string, amount float64, err error) {
+	if _, ok := s.fx.Rates[currency]; !ok && currency != "RUB" {
+		currency = "other"
+	}
+	metrics.ObserveTransfer(currency, amount, transferOutcome(err))
+}
+
+func transferOutcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.OutcomeSuccess
+	case errors.Is(err, repository.ErrLimitExceeded):
+		return metrics.OutcomeLimitExceeded
+	case errors.Is(err, repository.ErrInsufficientFunds):
+		return metrics.OutcomeInsufficientFunds
+	case errors.Is(err, repository.ErrAccountNotFound), errors.Is(err, ErrBeneficiaryMissing):
+		return metrics.OutcomeNotFound
+	case errors.Is(err, repository.ErrAccountFrozen), errors.Is(err, repository.ErrAccountClosed),
+		errors.Is(err, repository.ErrDebitBlocked), errors.Is(err, repository.ErrCreditBlocked):
+		return metrics.OutcomeAccountBlocked
+	case errors.Is(err, ErrStepUpRequired), errors.Is(err, ErrStepUpFailed),
+		errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrUnsupportedCurrency),
+		errors.Is(err, alias.ErrUnknownType), errors.Is(err, alias.ErrInvalid):
+		return metrics.OutcomeRejected
+	}
+	return metrics.OutcomeError
+}
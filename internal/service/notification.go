@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/notify"
+
+	"github.com/google/uuid"
+)
+
+const notificationHistoryLimit = 100
+
+// notificationData — данные для шаблонов уведомлений, заполняются поля нужного вида
+type notificationData struct {
+	Amount       float64
+	Currency     string
+	Counterparty string
+	Memo         string
+	Balance      float64
+	Limit        float64
+	Used         float64
+	UserAgent    string
+	IP           string
+	Time         time.Time
+}
+
+// defaultNotificationPreferences — настройки пользователя, который их не менял: все по email
+func defaultNotificationPreferences() models.NotificationPreferences {
+	channels := make(map[string][]string, len(models.NotificationKinds))
+	for _, kind := range models.NotificationKinds {
+		channels[kind] = []string{models.NotificationChannelEmail}
+	}
+	return models.NotificationPreferences{Language: models.LanguageRU, Channels: channels}
+}
+
+func (s *Service) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	p, err := s.repo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := defaultNotificationPreferences()
+	if p == nil {
+		return &defaults, nil
+	}
+	// Виды уведомлений, появившиеся после сохранения настроек, получают каналы по умолчанию
+	for kind, channels := range defaults.Channels {
+		if _, ok := p.Channels[kind]; !ok {
+			p.Channels[kind] = channels
+		}
+	}
+	return p, nil
+}
+
+// UpdateNotificationPreferences сохраняет настройки. Не указанные виды уведомлений получают
+// каналы по умолчанию, пустой список каналов отключает вид.
+func (s *Service) UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID, req models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	p := defaultNotificationPreferences()
+
+	if req.Language != "" {
+		if !contains(models.NotificationLanguages, req.Language) {
+			return nil, fmt.Errorf("unsupported language: %s", req.Language)
+		}
+		p.Language = req.Language
+	}
+
+	for kind, channels := range req.Channels {
+		if !contains(models.NotificationKinds, kind) {
+			return nil, fmt.Errorf("unknown notification kind: %s", kind)
+		}
+		result := []string{}
+		for _, channel := range channels {
+			if !contains(models.NotificationChannels, channel) {
+				return nil, fmt.Errorf("unknown notification channel: %s", channel)
+			}
+			if !contains(result, channel) {
+				result = append(result, channel)
+			}
+		}
+		p.Channels[kind] = result
+	}
+
+	if err := s.repo.SaveNotificationPreferences(ctx, userID, p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *Service) GetNotifications(ctx context.Context, userID uuid.UUID) ([]models.Notification, error) {
+	return s.repo.GetNotifications(ctx, userID, notificationHistoryLimit)
+}
+
+// HandleNotificationEvent — потребитель доменных событий: рассылает уведомления по каналам,
+// выбранным пользователем. Ошибка канала записывается в журнал доставки и не останавливает
+// поток событий; ошибка возвращается только при сбое базы, чтобы событие пришло повторно.
+func (s *Service) HandleNotificationEvent(ctx context.Context, e models.DomainEvent) error {
+	switch e.Type {
+	case models.EventTransferCompleted:
+		var data models.TransferEventData
+		if err := json.Unmarshal(e.Payload, &data); err != nil {
+			return nil
+		}
+		if err := s.notifyTransfer(ctx, e.ID, data.FromAccountID, data.ToAccountID, models.NotificationTransferSent, data); err != nil {
+			return err
+		}
+		return s.notifyTransfer(ctx, e.ID, data.ToAccountID, data.FromAccountID, models.NotificationTransferReceived, data)
+
+	case models.EventDepositCompleted:
+		var data models.DepositEventData
+		if err := json.Unmarshal(e.Payload, &data); err != nil {
+			return nil
+		}
+		account, err := s.repo.GetAccountByID(ctx, data.AccountID)
+		if err != nil || account == nil {
+			return err
+		}
+		return s.notifyUser(ctx, e.ID, account.UserID, models.NotificationDeposit, notificationData{
+			Amount:  data.Amount,
+			Balance: data.Balance,
+			Time:    e.CreatedAt,
+		})
+
+	case models.EventNewDeviceLogin:
+		var data models.NewDeviceLoginEventData
+		if err := json.Unmarshal(e.Payload, &data); err != nil {
+			return nil
+		}
+		return s.notifyUser(ctx, e.ID, data.UserID, models.NotificationNewDeviceLogin, notificationData{
+			UserAgent: data.UserAgent,
+			IP:        data.IP,
+			Time:      data.CreatedAt,
+		})
+
+	case models.EventLimitExceeded:
+		var data models.LimitExceededEventData
+		if err := json.Unmarshal(e.Payload, &data); err != nil {
+			return nil
+		}
+		account, err := s.repo.GetAccountByID(ctx, data.AccountID)
+		if err != nil || account == nil {
+			return err
+		}
+		return s.notifyUser(ctx, e.ID, account.UserID, models.NotificationLimitExceeded, notificationData{
+			Amount: data.Amount,
+			Used:   data.Used,
+			Limit:  data.Limit,
+			Time:   data.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func (s *Service) notifyTransfer(ctx context.Context, eventID, accountID, counterpartyID uuid.UUID, kind string, data models.TransferEventData) error {
+	account, err := s.repo.GetAccountByID(ctx, accountID)
+	if err != nil || account == nil {
+		return err
+	}
+
+	values := notificationData{
+		Amount:   data.Amount,
+		Currency: data.Currency,
+		Memo:     data.Memo,
+		Time:     data.CreatedAt,
+	}
+	if counterparty, err := s.repo.GetAccountByID(ctx, counterpartyID); err != nil {
+		return err
+	} else if counterparty != nil {
+		user, err := s.repo.GetUserByID(ctx, counterparty.UserID)
+		if err != nil {
+			return err
+		}
+		if user != nil {
+			values.Counterparty = user.Email
+		}
+	}
+	balance, err := s.repo.GetBalance(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	if balance != nil {
+		values.Balance = balance.Available
+	}
+
+	return s.notifyUser(ctx, eventID, account.UserID, kind, values)
+}
+
+// notifyUser отправляет уведомление по всем включенным каналам и записывает результат.
+// Уже доставленные по этому событию каналы пропускаются.
+func (s *Service) notifyUser(ctx context.Context, eventID, userID uuid.UUID, kind string, data notificationData) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil || user == nil {
+		return err
+	}
+	prefs, err := s.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	msg, err := notify.Render(kind, prefs.Language, data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to render notification", "kind", kind, "user_id", userID, "error", err)
+		return nil
+	}
+
+	for _, channel := range prefs.Channels[kind] {
+		notifier, ok := s.notifiers[channel]
+		if !ok {
+			continue
+		}
+		sent, err := s.repo.NotificationSent(ctx, eventID, userID, channel)
+		if err != nil {
+			return err
+		}
+		if sent {
+			continue
+		}
+
+		to, err := s.notificationAddress(ctx, user, channel)
+		if err != nil {
+			return err
+		}
+
+		n := models.Notification{
+			UserID:    userID,
+			EventID:   eventID,
+			Kind:      kind,
+			Channel:   channel,
+			Recipient: to,
+			Subject:   msg.Subject,
+			Body:      msg.Body,
+		}
+		if to == "" {
+			n.Status = models.NotificationSkipped
+			n.Error = "no address for channel"
+		} else {
+			msg.To = to
+			if err := notifier.Send(ctx, msg); err != nil {
+				n.Status = models.NotificationFailed
+				n.Error = err.Error()
+			} else {
+				now := time.Now()
+				n.Status = models.NotificationSent
+				n.SentAt = &now
+			}
+		}
+
+		if err := s.repo.SaveNotification(ctx, &n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notificationAddress — адрес пользователя в канале: email, подтвержденный телефон
+// или идентификатор пользователя для push. Пустая строка — адреса нет.
+func (s *Service) notificationAddress(ctx context.Context, user *models.User, channel string) (string, error) {
+	switch channel {
+	case models.NotificationChannelEmail:
+		return user.Email, nil
+	case models.NotificationChannelSMS:
+		aliases, err := s.repo.GetAliasesByUser(ctx, user.ID)
+		if err != nil {
+			return "", err
+		}
+		for _, a := range aliases {
+			if a.Type == models.AliasPhone && a.Verified {
+				return a.Value, nil
+			}
+		}
+		return "", nil
+	case models.NotificationChannelPush:
+		return user.ID.String(), nil
+	}
+	return "", nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const maxPaymentLinkDuration = 365 * 24 * time.Hour
+
+var ErrPaymentLinkNotFound = errors.New("payment link not found")
+
+func (s *Service) CreatePaymentLink(ctx context.Context, userID uuid.UUID, req models.CreatePaymentLinkRequest) (*models.PaymentLink, error) {
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("expiry must be in the future")
+		}
+		if time.Until(*req.ExpiresAt) > maxPaymentLinkDuration {
+			return nil, fmt.Errorf("expiry must be within %d days", int(maxPaymentLinkDuration.Hours()/24))
+		}
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = "RUB"
+	}
+	// Проверяем, что валюта поддерживается, до того как ссылка попадет к плательщику
+	if _, err := s.toRUB(ctx, 1, currency); err != nil {
+		return nil, err
+	}
+
+	details, err := normalizeTransferDetails(models.TransferDetails{Memo: req.Memo})
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+
+	return s.repo.CreatePaymentLink(ctx, account.ID, req.Amount, currency, details.Memo, req.SingleUse, req.ExpiresAt)
+}
+
+func (s *Service) GetPaymentLinks(ctx context.Context, userID uuid.UUID) ([]models.PaymentLink, error) {
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+	return s.repo.GetPaymentLinksByAccount(ctx, account.ID)
+}
+
+// GetPaymentLinkPayments возвращает платежи по ссылке; видны только ее владельцу
+func (s *Service) GetPaymentLinkPayments(ctx context.Context, userID uuid.UUID, code string) ([]models.PaymentLinkPayment, error) {
+	link, err := s.getOwnPaymentLink(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetPaymentLinkPayments(ctx, link.ID)
+}
+
+func (s *Service) CancelPaymentLink(ctx context.Context, userID uuid.UUID, code string) error {
+	link, err := s.getOwnPaymentLink(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	cancelled, err := s.repo.CancelPaymentLink(ctx, link.AccountID, link.ID)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return repository.ErrPaymentLinkInactive
+	}
+	return nil
+}
+
+func (s *Service) getOwnPaymentLink(ctx context.Context, userID uuid.UUID, code string) (*models.PaymentLink, error) {
+	link, err := s.repo.GetPaymentLinkByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, ErrPaymentLinkNotFound
+	}
+
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil || account.ID != link.AccountID {
+		return nil, ErrPaymentLinkNotFound
+	}
+	return link, nil
+}
+
+// GetPublicPaymentLink — данные ссылки для любого, кто ее открыл: имя получателя маскируется
+func (s *Service) GetPublicPaymentLink(ctx context.Context, code string) (*models.PublicPaymentLink, error) {
+	link, err := s.repo.GetPaymentLinkByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, ErrPaymentLinkNotFound
+	}
+
+	account, err := s.repo.GetAccountByID(ctx, link.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrPaymentLinkNotFound
+	}
+	owner, err := s.repo.GetUserByID(ctx, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, ErrPaymentLinkNotFound
+	}
+
+	return &models.PublicPaymentLink{
+		Code:          link.Code,
+		RecipientName: MaskFullName(owner.FullName),
+		Amount:        link.Amount,
+		Currency:      link.Currency,
+		Memo:          link.Memo,
+		Status:        link.Status,
+		ExpiresAt:     link.ExpiresAt,
+	}, nil
+}
+
+// PayPaymentLink оплачивает ссылку. Для ссылки с фиксированной суммой сумму можно не указывать.
+func (s *Service) PayPaymentLink(ctx context.Context, userID uuid.UUID, code string, req models.PayPaymentLinkRequest) (uuid.UUID, error) {
+	link, err := s.repo.GetPaymentLinkByCode(ctx, code)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if link == nil {
+		return uuid.Nil, ErrPaymentLinkNotFound
+	}
+	if link.Status != models.PaymentLinkStatusActive {
+		return uuid.Nil, repository.ErrPaymentLinkInactive
+	}
+
+	amount := req.Amount
+	if link.Amount != nil {
+		if amount != 0 && amount != *link.Amount {
+			return uuid.Nil, fmt.Errorf("amount must be %.2f %s for this payment link", *link.Amount, link.Currency)
+		}
+		amount = *link.Amount
+	}
+	if amount <= 0 {
+		return uuid.Nil, ErrInvalidAmount
+	}
+
+	fromAccount, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if fromAccount == nil {
+		return uuid.Nil, fmt.Errorf("sender %w", repository.ErrAccountNotFound)
+	}
+	if fromAccount.ID == link.AccountID {
+		return uuid.Nil, fmt.Errorf("cannot pay your own payment link")
+	}
+
+	amountRUB, err := s.toRUB(ctx, amount, link.Currency)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := s.checkStepUp(ctx, userID, link.AccountID, amountRUB, req.Password); err != nil {
+		return uuid.Nil, err
+	}
+
+	details := models.TransferDetails{Memo: link.Memo, Reference: link.Code}
+	return s.repo.PayPaymentLink(ctx, link.ID, fromAccount.ID, amountRUB, amount, link.Currency, details)
+}
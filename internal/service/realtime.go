@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/realtime"
+
+	"github.com/google/uuid"
+)
+
+// transferUpdate — перевод глазами одной из сторон, Counterparty — email другой стороны
+type transferUpdate struct {
+	models.TransferEventData
+	Counterparty string `json:"counterparty"`
+}
+
+// RunRealtime раздает обновления из Redis открытым сессиям этого экземпляра
+func (s *Service) RunRealtime(ctx context.Context) {
+	s.realtime.Run(ctx)
+}
+
+func (s *Service) SubscribeUpdates(userID uuid.UUID) (<-chan realtime.Message, func()) {
+	return s.realtime.Subscribe(userID)
+}
+
+// PublishRealtimeEvent — потребитель доменных событий: превращает их в обновления для
+// сессий владельцев затронутых счетов. Баланс отправляется актуальный на момент обработки.
+func (s *Service) PublishRealtimeEvent(ctx context.Context, e models.DomainEvent) error {
+	switch e.Type {
+	case models.EventTransferCompleted:
+		var data models.TransferEventData
+		if err := json.Unmarshal(e.Payload, &data); err != nil {
+			return nil
+		}
+		if err := s.publishTransferUpdate(ctx, data.FromAccountID, data.ToAccountID, realtime.MessageTransferSent, data); err != nil {
+			return err
+		}
+		return s.publishTransferUpdate(ctx, data.ToAccountID, data.FromAccountID, realtime.MessageTransferReceived, data)
+
+	case models.EventDepositCompleted:
+		var data models.DepositEventData
+		if err := json.Unmarshal(e.Payload, &data); err != nil {
+			return nil
+		}
+		account, err := s.repo.GetAccountByID(ctx, data.AccountID)
+		if err != nil || account == nil {
+			return err
+		}
+		return s.publishBalance(ctx, account)
+
+	case models.EventAccountStatusChanged:
+		var data models.AccountStatusEventData
+		if err := json.Unmarshal(e.Payload, &data); err != nil {
+			return nil
+		}
+		account, err := s.repo.GetAccountByID(ctx, data.AccountID)
+		if err != nil || account == nil {
+			return err
+		}
+		return s.realtime.Publish(ctx, account.UserID, realtime.MessageAccountStatus, data)
+	}
+	return nil
+}
+
+func (s *Service) publishTransferUpdate(ctx context.Context, accountID, counterpartyID uuid.UUID, msgType string, data models.TransferEventData) error {
+	account, err := s.repo.GetAccountByID(ctx, accountID)
+	if err != nil || account == nil {
+		return err
+	}
+
+	update := transferUpdate{TransferEventData: data}
+	if counterparty, err := s.repo.GetAccountByID(ctx, counterpartyID); err != nil {
+		return err
+	} else if counterparty != nil {
+		user, err := s.repo.GetUserByID(ctx, counterparty.UserID)
+		if err != nil {
+			return err
+		}
+		if user != nil {
+			update.Counterparty = user.Email
+		}
+	}
+
+	if err := s.realtime.Publish(ctx, account.UserID, msgType, update); err != nil {
+		return err
+	}
+	return s.publishBalance(ctx, account)
+}
+
+func (s *Service) publishBalance(ctx context.Context, account *models.Account) error {
+	balance, err := s.repo.GetBalance(ctx, account.ID)
+	if err != nil || balance == nil {
+		return err
+	}
+	return s.realtime.Publish(ctx, account.UserID, realtime.MessageBalance, balance)
+}
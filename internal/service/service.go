@@ -2,47 +2,105 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"strconv"
-	"time"
 
+	"money-transfer-service/internal/alias"
 	"money-transfer-service/internal/cache"
+	"money-transfer-service/internal/config"
+	"money-transfer-service/internal/metrics"
 	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/notify"
+	"money-transfer-service/internal/realtime"
 	"money-transfer-service/internal/repository"
+	"money-transfer-service/internal/tracing"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	ErrInvalidAmount       = errors.New("amount must be positive")
+	ErrUnsupportedCurrency = errors.New("currency not supported")
 )
 
 type Service struct {
-	repo  *repository.Repository
-	cache *cache.RedisClient
+	repo       *repository.Repository
+	cache      *cache.RedisClient
+	aliases    *alias.Directory
+	codeSender alias.CodeSender
+	realtime   *realtime.Hub
+	notifiers  map[string]notify.Notifier
+	limits     config.LimitsConfig
+	fx         config.FXConfig
+	batchWake  chan struct{}
+	// Ключ токенов отмены в checkout_url, производный от секрета JWT
+	checkoutKey []byte
 }
 
 func (s *Service) GetTransfersHistory(ctx context.Context, accountID uuid.UUID) ([]models.Transfer, error) {
 	return s.repo.GetTransfersByAccount(ctx, accountID)
 }
-func NewService(repo *repository.Repository, cache *cache.RedisClient) *Service {
-	return &Service{repo: repo, cache: cache}
+func NewService(repo *repository.Repository, cache *cache.RedisClient, cfg *config.Config) *Service {
+	notifiers := notify.New(cfg.Notify)
+
+	// Коды подтверждения телефонов уходят по SMS. Без SMS-шлюза их можно только
+	// писать в лог, и только при локальной разработке; иначе подтверждение телефона недоступно.
+	var codeSender alias.CodeSender
+	switch {
+	case cfg.Notify.SMSGatewayURL != "":
+		codeSender = alias.SMSCodeSender{SMS: notifiers[models.NotificationChannelSMS]}
+	case cfg.Development():
+		codeSender = alias.LogCodeSender{}
+	}
+
+	return &Service{
+		repo:        repo,
+		cache:       cache,
+		aliases:     alias.NewDirectory(repo),
+		codeSender:  codeSender,
+		realtime:    realtime.NewHub(cache.Client()),
+		notifiers:   notifiers,
+		limits:      cfg.Limits,
+		fx:          cfg.FX,
+		batchWake:   make(chan struct{}, 1),
+		checkoutKey: deriveCheckoutKey(cfg.JWT.Secret.Value()),
+	}
 }
 
-func (s *Service) GetBalance(ctx context.Context, accountID uuid.UUID) (float64, error) {
-	return s.repo.GetBalance(ctx, accountID)
+func (s *Service) GetBalance(ctx context.Context, accountID uuid.UUID) (*models.Balance, error) {
+	balance, err := s.repo.GetBalance(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if balance == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+	return balance, nil
 }
-func (s *Service) DepositMoney(ctx context.Context, accountID uuid.UUID, amount float64) error {
+func (s *Service) DepositMoney(ctx context.Context, accountID uuid.UUID, amount float64) (err error) {
+	ctx, span := tracing.Start(ctx, "Service.DepositMoney")
+	defer func() { tracing.End(span, err) }()
+
 	if amount <= 0 {
-		return fmt.Errorf("amount must be positive")
+		return ErrInvalidAmount
 	}
 	return s.repo.DepositMoney(ctx, accountID, amount)
 }
-func (s *Service) TransferMoney(ctx context.Context, req models.TransferRequest) error {
+func (s *Service) TransferMoney(ctx context.Context, req models.TransferRequest) (transferID uuid.UUID, err error) {
+	ctx, span := tracing.Start(ctx, "Service.TransferMoney")
+	defer func() { tracing.End(span, err) }()
+	defer func() { s.observeTransfer(req.Currency, req.Amount, err) }()
+
 	fromID, err := uuid.Parse(req.From)
 	if err != nil {
-		return fmt.Errorf("invalid from account ID")
+		return uuid.Nil, fmt.Errorf("invalid from account ID")
 	}
 	toID, err := uuid.Parse(req.To)
 	if err != nil {
-		return fmt.Errorf("invalid to account ID")
+		return uuid.Nil, fmt.Errorf("invalid to account ID")
 	}
 
 	// Для конвертации используем исходную сумму и валюту
@@ -50,68 +108,97 @@ func (s *Service) TransferMoney(ctx context.Context, req models.TransferRequest)
 	if req.Currency != "RUB" {
 		rate, err := s.getExchangeRate(ctx, req.Currency)
 		if err != nil {
-			return fmt.Errorf("failed to get exchange rate: %w", err)
+			return uuid.Nil, fmt.Errorf("failed to get exchange rate: %w", err)
 		}
 		amountToTransfer = req.Amount * rate
 	}
 
-	return s.repo.TransferMoney(ctx, fromID, toID, amountToTransfer, req.Currency)
+	return s.repo.TransferMoney(ctx, fromID, toID, amountToTransfer, req.Currency, req.TransferDetails)
+}
+
+func (s *Service) TransferMoneyByEmail(ctx context.Context, fromUserID uuid.UUID, toEmail string, amount float64, currency string, details models.TransferDetails, password string) (uuid.UUID, error) {
+	recipient := models.Recipient{Type: models.AliasEmail, Value: toEmail}
+	return s.TransferToRecipient(ctx, fromUserID, recipient, amount, currency, details, password)
 }
 
-func (s *Service) TransferMoneyByEmail(ctx context.Context, fromUserID uuid.UUID, toEmail string, amount float64, currency string) error {
+// Переводы контактам, сохраненным с паролем, не требуют его повторного ввода
+func (s *Service) TransferToBeneficiary(ctx context.Context, fromUserID, beneficiaryID uuid.UUID, amount float64, currency string, details models.TransferDetails, password string) (transferID uuid.UUID, err error) {
+	ctx, span := tracing.Start(ctx, "Service.TransferToBeneficiary")
+	defer func() { tracing.End(span, err) }()
+	defer func() { s.observeTransfer(currency, amount, err) }()
+
+	b, err := s.repo.GetBeneficiary(ctx, fromUserID, beneficiaryID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if b == nil {
+		return uuid.Nil, ErrBeneficiaryMissing
+	}
+
+	return s.transferFromUser(ctx, fromUserID, b.AccountID, amount, currency, details, password)
+}
+
+func (s *Service) transferFromUser(ctx context.Context, fromUserID, toAccountID uuid.UUID, amount float64, currency string, details models.TransferDetails, password string) (uuid.UUID, error) {
 	fromAccount, err := s.repo.GetAccountByUserID(ctx, fromUserID)
 	if err != nil {
-		return err
+		return uuid.Nil, err
 	}
 	if fromAccount == nil {
-		return fmt.Errorf("sender account not found")
+		return uuid.Nil, fmt.Errorf("sender %w", repository.ErrAccountNotFound)
 	}
 
-	toAccount, err := s.repo.GetAccountByEmail(ctx, toEmail)
+	details, err = normalizeTransferDetails(details)
 	if err != nil {
-		return err
-	}
-	if toAccount == nil {
-		return fmt.Errorf("recipient account not found for email: %s", toEmail)
+		return uuid.Nil, err
 	}
 
 	// Для конвертации используем исходную сумму и валюту
-	amountToTransfer := amount
-	if currency != "RUB" {
-		rate, err := s.getExchangeRate(ctx, currency)
-		if err != nil {
-			return fmt.Errorf("failed to get exchange rate: %w", err)
-		}
-		amountToTransfer = amount * rate
+	amountToTransfer, err := s.toRUB(ctx, amount, currency)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := s.checkStepUp(ctx, fromUserID, toAccountID, amountToTransfer, password); err != nil {
+		return uuid.Nil, err
 	}
 
-	return s.repo.TransferMoney(ctx, fromAccount.ID, toAccount.ID, amountToTransfer, currency)
+	return s.repo.TransferMoney(ctx, fromAccount.ID, toAccountID, amountToTransfer, currency, details)
 }
 
-func (s *Service) getExchangeRate(ctx context.Context, currency string) (float64, error) {
+func (s *Service) toRUB(ctx context.Context, amount float64, currency string) (float64, error) {
+	if currency == "RUB" {
+		return amount, nil
+	}
+	rate, err := s.getExchangeRate(ctx, currency)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	return amount * rate, nil
+}
+
+func (s *Service) getExchangeRate(ctx context.Context, currency string) (rate float64, err error) {
+	ctx, span := tracing.Start(ctx, "Service.getExchangeRate", attribute.String("currency", currency))
+	defer func() { tracing.End(span, err) }()
+
 	cachedRate, err := s.cache.Get(ctx, currency)
 	if err == nil {
 		rate, err := strconv.ParseFloat(cachedRate, 64)
 		if err == nil {
+			metrics.ObserveRateCache(true)
 			return rate, nil
 		}
 	}
+	metrics.ObserveRateCache(false)
 
-	// Актуальные курсы (1 USD/EUR к RUB)
-	rates := map[string]float64{
-		"USD": 90.0,  // 1 USD = 90 RUB
-		"EUR": 100.0, // 1 EUR = 100 RUB
-	}
-
-	rate, ok := rates[currency]
+	// Курсы к RUB из конфигурации
+	rate, ok := s.fx.Rates[currency]
 	if !ok {
-		return 0, fmt.Errorf("currency not supported: %s", currency)
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
 	}
 
-	// Кэшируем на 5 минут
-	err = s.cache.Set(ctx, currency, fmt.Sprintf("%f", rate), 5*time.Minute)
+	err = s.cache.Set(ctx, currency, fmt.Sprintf("%f", rate), s.fx.CacheTTL)
 	if err != nil {
-		log.Printf("Cache set error: %v", err)
+		slog.WarnContext(ctx, "failed to cache exchange rate", "currency", currency, "error", err)
 	}
 
 	return rate, nil
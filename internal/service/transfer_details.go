@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxMemoLength      = 500
+	maxReferenceLength = 100
+	maxTagsPerTransfer = 10
+	maxTagLength       = 50
+	searchResultsLimit = 50
+)
+
+func normalizeTransferDetails(details models.TransferDetails) (models.TransferDetails, error) {
+	details.Memo = strings.TrimSpace(details.Memo)
+	details.Reference = strings.TrimSpace(details.Reference)
+
+	if utf8.RuneCountInString(details.Memo) > maxMemoLength {
+		return details, fmt.Errorf("memo must be at most %d characters", maxMemoLength)
+	}
+	if utf8.RuneCountInString(details.Reference) > maxReferenceLength {
+		return details, fmt.Errorf("reference must be at most %d characters", maxReferenceLength)
+	}
+	return details, nil
+}
+
+// Теги приводятся к нижнему регистру, дубликаты отбрасываются
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag must be at most %d characters", maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > maxTagsPerTransfer {
+		return nil, fmt.Errorf("at most %d tags per transfer are allowed", maxTagsPerTransfer)
+	}
+	return normalized, nil
+}
+
+func (s *Service) SetTransferTags(ctx context.Context, accountID, transferID uuid.UUID, tags []string) ([]string, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetTransferTags(ctx, accountID, transferID, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func (s *Service) SearchTransfers(ctx context.Context, accountID uuid.UUID, query string) ([]models.Transfer, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+	return s.repo.SearchTransfers(ctx, accountID, query, searchResultsLimit)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"money-transfer-service/internal/models"
+	"money-transfer-service/internal/webhook"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxWebhookEndpoints  = 10
+	webhookDeliveryLimit = 100
+)
+
+var (
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// validateWebhookURL — validateCallbackURL и запрет внутренних адресов: на этот URL ходит сам сервис.
+// Имена, разрешающиеся во внутренние адреса, отсекает диспетчер при соединении.
+func validateWebhookURL(field, raw string) error {
+	if err := validateCallbackURL(field, raw); err != nil {
+		return err
+	}
+	u, _ := neturl.Parse(raw)
+	if err := webhook.CheckHost(u.Hostname()); err != nil {
+		return fmt.Errorf("%s must not point to a loopback, private or link-local address", field)
+	}
+	return nil
+}
+
+// normalizeWebhookEvents проверяет типы событий и убирает повторы
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("at least one event is required")
+	}
+
+	known := make(map[string]bool, len(models.WebhookEvents))
+	for _, e := range models.WebhookEvents {
+		known[e] = true
+	}
+
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if !known[e] {
+			return nil, fmt.Errorf("unknown event type: %s", e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// CreateWebhookEndpoint регистрирует эндпоинт. Секрет для проверки подписи возвращается только здесь
+// и при ротации.
+func (s *Service) CreateWebhookEndpoint(ctx context.Context, userID uuid.UUID, req models.CreateWebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	url := strings.TrimSpace(req.URL)
+	if err := validateWebhookURL("url", url); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetWebhookEndpoints(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhookEndpoints {
+		return nil, fmt.Errorf("webhook endpoint limit reached (%d)", maxWebhookEndpoints)
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateWebhookEndpoint(ctx, userID, url, secret, events)
+}
+
+func (s *Service) GetWebhookEndpoints(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error) {
+	return s.repo.GetWebhookEndpoints(ctx, userID)
+}
+
+func (s *Service) GetWebhookEndpoint(ctx context.Context, userID, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	e, err := s.repo.GetWebhookEndpoint(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	return e, nil
+}
+
+func (s *Service) UpdateWebhookEndpoint(ctx context.Context, userID, id uuid.UUID, req models.UpdateWebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	e, err := s.GetWebhookEndpoint(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		url := strings.TrimSpace(*req.URL)
+		if err := validateWebhookURL("url", url); err != nil {
+			return nil, err
+		}
+		e.URL = url
+	}
+	if req.Events != nil {
+		if e.Events, err = normalizeWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+	if req.Active != nil {
+		e.Active = *req.Active
+	}
+
+	updated, err := s.repo.UpdateWebhookEndpoint(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	return updated, nil
+}
+
+func (s *Service) RotateWebhookSecret(ctx context.Context, userID, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := s.repo.RotateWebhookSecret(ctx, userID, id, secret)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	return e, nil
+}
+
+func (s *Service) DeleteWebhookEndpoint(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.repo.DeleteWebhookEndpoint(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookEndpointNotFound
+	}
+	return nil
+}
+
+func (s *Service) GetWebhookDeliveries(ctx context.Context, userID, endpointID uuid.UUID, status string) ([]models.WebhookDelivery, error) {
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		return nil, fmt.Errorf("invalid delivery status: %s", status)
+	}
+
+	if _, err := s.GetWebhookEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetWebhookDeliveries(ctx, endpointID, status, webhookDeliveryLimit)
+}
+
+// Redeliver повторно отправляет событие из журнала, например после исправления обработчика
+func (s *Service) Redeliver(ctx context.Context, userID, endpointID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.GetWebhookEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+
+	d, err := s.repo.Redeliver(ctx, endpointID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return d, nil
+}
+
+func (s *Service) SendTestWebhook(ctx context.Context, userID, endpointID uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.GetWebhookEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	return s.repo.EnqueueTestWebhook(ctx, endpointID)
+}
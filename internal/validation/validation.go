@@ -0,0 +1,149 @@
+// Package validation проверяет DTO запросов по тегам validate (go-playground/validator)
+// и возвращает ошибки по полям с именами из json-тегов.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError — ошибка одного поля. Code — имя нарушенного правила (required, email, max,
+// currency...), Param — его параметр: по ним фронтенд строит локализованный текст.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return IsSupportedCurrency(fl.Field().String())
+	})
+	return v
+}
+
+var (
+	currencyMu sync.RWMutex
+	currencies = map[string]bool{"RUB": true}
+)
+
+// SetSupportedCurrencies задает валюты, которые принимает сервис (RUB и валюты
+// из справочника курсов). Вызывается при старте.
+func SetSupportedCurrencies(codes []string) error {
+	supported := map[string]bool{}
+	for _, code := range codes {
+		if !IsISO4217(code) {
+			return fmt.Errorf("%q is not an ISO 4217 currency code", code)
+		}
+		supported[code] = true
+	}
+	currencyMu.Lock()
+	currencies = supported
+	currencyMu.Unlock()
+	return nil
+}
+
+func IsSupportedCurrency(code string) bool {
+	currencyMu.RLock()
+	defer currencyMu.RUnlock()
+	return currencies[code]
+}
+
+// IsISO4217 проверяет буквенный код валюты по справочнику ISO 4217
+func IsISO4217(code string) bool {
+	return validate.Var(code, "iso4217") == nil
+}
+
+// Struct проверяет структуру и возвращает ошибки по полям; nil — все в порядке
+func Struct(v interface{}) []FieldError {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		// Не структура — ошибка в коде вызывающего, а не во входных данных
+		panic(err)
+	}
+
+	// Namespace начинается с имени типа: "RegisterRequest.email". У анонимных структур имени нет.
+	root := reflect.Indirect(reflect.ValueOf(v)).Type().Name()
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, FieldError{
+			Field:   strings.TrimPrefix(fe.Namespace(), root+"."),
+			Code:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: message(fe),
+		})
+	}
+	return fields
+}
+
+func message(fe validator.FieldError) string {
+	kind := fe.Kind()
+	isString := kind == reflect.String
+	isList := kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "numeric":
+		return "must contain only digits"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "currency":
+		return "is not a supported currency"
+	case "len":
+		if isString {
+			return fmt.Sprintf("must be exactly %s characters long", fe.Param())
+		}
+		return fmt.Sprintf("must have exactly %s items", fe.Param())
+	case "min":
+		switch {
+		case isString:
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		case isList:
+			return fmt.Sprintf("must have at least %s items", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		switch {
+		case isString:
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		case isList:
+			return fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte":
+		return "must be less than or equal to " + fe.Param()
+	}
+	return "is invalid"
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxAttempts — после стольких неудач доставка помечается как failed
+	MaxAttempts = 10
+
+	batchSize   = 50
+	lease       = time.Minute
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+)
+
+// Backoff возвращает задержку перед следующей попыткой: 30s, 1m, 2m, ... но не больше 6 часов
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+// Store — очередь доставок, которую разбирает Dispatcher
+type Store interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDispatch, error)
+	RecordWebhookAttempt(ctx context.Context, id uuid.UUID, statusCode *int, lastError string, delivered bool, nextAttemptAt *time.Time) error
+}
+
+type Dispatcher struct {
+	repo   Store
+	client *http.Client
+}
+
+func NewDispatcher(repo Store) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: newHTTPClient(),
+	}
+}
+
+// Run периодически отправляет накопившиеся события, пока не отменен контекст
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DeliverDue(ctx); err != nil {
+			slog.ErrorContext(ctx, "webhook delivery failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) DeliverDue(ctx context.Context) error {
+	for {
+		dispatches, err := d.repo.ClaimWebhookDeliveries(ctx, batchSize, lease)
+		if err != nil {
+			return err
+		}
+
+		for _, dispatch := range dispatches {
+			if err := d.deliver(ctx, dispatch); err != nil {
+				slog.ErrorContext(ctx, "failed to record webhook attempt", "delivery_id", dispatch.Delivery.ID, "error", err)
+			}
+		}
+
+		if len(dispatches) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, dispatch models.WebhookDispatch) error {
+	delivery := dispatch.Delivery
+	statusCode, err := d.send(ctx, dispatch)
+	if err == nil {
+		return d.repo.RecordWebhookAttempt(ctx, delivery.ID, &statusCode, "", true, nil)
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+
+	attempt := delivery.Attempts + 1
+	var next *time.Time
+	if attempt < MaxAttempts {
+		t := time.Now().Add(Backoff(attempt))
+		next = &t
+	}
+	slog.WarnContext(ctx, "webhook attempt failed", "delivery_id", delivery.ID, "url", dispatch.URL, "attempt", attempt, "error", err)
+	return d.repo.RecordWebhookAttempt(ctx, delivery.ID, code, err.Error(), false, next)
+}
+
+func (d *Dispatcher) send(ctx context.Context, dispatch models.WebhookDispatch) (int, error) {
+	body := []byte(dispatch.Delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dispatch.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "money-transfer-service-webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", dispatch.Delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", dispatch.Delivery.ID.String())
+	req.Header.Set(SignatureHeader, Sign(dispatch.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,252 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"money-transfer-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const testSecret = "whsec_test"
+
+type attempt struct {
+	id         uuid.UUID
+	statusCode *int
+	lastError  string
+	delivered  bool
+	next       *time.Time
+}
+
+// memoryStore — очередь доставок в памяти: Claim отдает поставленные доставки один раз
+type memoryStore struct {
+	mu       sync.Mutex
+	queue    []models.WebhookDispatch
+	attempts []attempt
+}
+
+func (s *memoryStore) enqueue(d models.WebhookDispatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, d)
+}
+
+func (s *memoryStore) ClaimWebhookDeliveries(_ context.Context, limit int, _ time.Duration) ([]models.WebhookDispatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := min(limit, len(s.queue))
+	claimed := s.queue[:n:n]
+	s.queue = s.queue[n:]
+	return claimed, nil
+}
+
+func (s *memoryStore) RecordWebhookAttempt(_ context.Context, id uuid.UUID, statusCode *int, lastError string, delivered bool, next *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts = append(s.attempts, attempt{id, statusCode, lastError, delivered, next})
+	return nil
+}
+
+func (s *memoryStore) lastAttempt(t *testing.T) attempt {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.attempts) == 0 {
+		t.Fatal("no attempts recorded")
+	}
+	return s.attempts[len(s.attempts)-1]
+}
+
+type received struct {
+	deliveryID string
+	eventType  string
+	err        error
+}
+
+// receiver — подписчик, который проверяет подпись и отвечает статусами из statuses по очереди,
+// а после них — 204
+func receiver(t *testing.T, statuses ...int) (*httptest.Server, func() []received) {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		reqs []received
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, received{
+			deliveryID: r.Header.Get("X-Webhook-Delivery"),
+			eventType:  r.Header.Get("X-Webhook-Event"),
+			err:        Verify(testSecret, r.Header.Get(SignatureHeader), body, 5*time.Minute),
+		})
+		n := len(reqs)
+		mu.Unlock()
+
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []received {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]received(nil), reqs...)
+	}
+}
+
+// testDispatcher ходит на локальный приемник: защита от внутренних адресов проверяется отдельно
+func testDispatcher(store Store, srv *httptest.Server) *Dispatcher {
+	d := NewDispatcher(store)
+	d.client = srv.Client()
+	return d
+}
+
+func dispatchTo(url string, attempts int) models.WebhookDispatch {
+	payload, _ := json.Marshal(map[string]string{"type": models.EventTransferCompleted})
+	return models.WebhookDispatch{
+		Delivery: models.WebhookDelivery{
+			ID:        uuid.New(),
+			EventType: models.EventTransferCompleted,
+			Payload:   payload,
+			Attempts:  attempts,
+		},
+		URL:    url,
+		Secret: testSecret,
+	}
+}
+
+func TestDeliverSignedEvent(t *testing.T) {
+	srv, requests := receiver(t)
+	store := &memoryStore{}
+	dispatch := dispatchTo(srv.URL, 0)
+	store.enqueue(dispatch)
+
+	if err := testDispatcher(store, srv).DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(reqs))
+	}
+	if reqs[0].err != nil {
+		t.Errorf("signature check failed: %v", reqs[0].err)
+	}
+	if reqs[0].deliveryID != dispatch.Delivery.ID.String() || reqs[0].eventType != models.EventTransferCompleted {
+		t.Errorf("headers = %+v, want delivery %s", reqs[0], dispatch.Delivery.ID)
+	}
+
+	got := store.lastAttempt(t)
+	if !got.delivered || got.statusCode == nil || *got.statusCode != http.StatusNoContent {
+		t.Errorf("attempt = %+v, want delivered with 204", got)
+	}
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	srv, requests := receiver(t, http.StatusServiceUnavailable)
+	store := &memoryStore{}
+	d := testDispatcher(store, srv)
+	dispatch := dispatchTo(srv.URL, 0)
+
+	store.enqueue(dispatch)
+	before := time.Now()
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := store.lastAttempt(t)
+	if failed.delivered || failed.statusCode == nil || *failed.statusCode != http.StatusServiceUnavailable {
+		t.Fatalf("attempt = %+v, want failed with 503", failed)
+	}
+	if failed.next == nil {
+		t.Fatal("next attempt not scheduled after 5xx")
+	}
+	if wait := failed.next.Sub(before); wait < Backoff(1) || wait > Backoff(1)+time.Minute {
+		t.Errorf("next attempt in %v, want about %v", wait, Backoff(1))
+	}
+
+	// Очередь снова отдает доставку, когда подошло время следующей попытки
+	dispatch.Delivery.Attempts = 1
+	store.enqueue(dispatch)
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.lastAttempt(t); !got.delivered {
+		t.Errorf("retry attempt = %+v, want delivered", got)
+	}
+	if n := len(requests()); n != 2 {
+		t.Errorf("receiver got %d requests, want 2", n)
+	}
+}
+
+func TestGiveUpAfterMaxAttempts(t *testing.T) {
+	srv, _ := receiver(t, http.StatusInternalServerError)
+	store := &memoryStore{}
+	store.enqueue(dispatchTo(srv.URL, MaxAttempts-1))
+
+	if err := testDispatcher(store, srv).DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.lastAttempt(t); got.delivered || got.next != nil {
+		t.Errorf("attempt = %+v, want failed without next attempt", got)
+	}
+}
+
+func TestRedelivery(t *testing.T) {
+	srv, requests := receiver(t)
+	store := &memoryStore{}
+	d := testDispatcher(store, srv)
+
+	original := dispatchTo(srv.URL, MaxAttempts)
+	redelivery := original
+	redelivery.Delivery.ID = uuid.New()
+	redelivery.Delivery.RedeliveryOf = &original.Delivery.ID
+	redelivery.Delivery.Attempts = 0
+	store.enqueue(redelivery)
+
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(reqs))
+	}
+	if reqs[0].err != nil {
+		t.Errorf("redelivery signature check failed: %v", reqs[0].err)
+	}
+	if reqs[0].deliveryID != redelivery.Delivery.ID.String() {
+		t.Errorf("X-Webhook-Delivery = %s, want the new delivery %s", reqs[0].deliveryID, redelivery.Delivery.ID)
+	}
+	if got := store.lastAttempt(t); got.id != redelivery.Delivery.ID || !got.delivered {
+		t.Errorf("attempt = %+v, want redelivery delivered", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, 4*time.Hour + 16*time.Minute},
+		{20, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenDestination — адрес подписчика ведет во внутреннюю сеть сервиса
+var ErrForbiddenDestination = errors.New("webhook destination is not allowed")
+
+// allowedAddr отсекает адреса, по которым вебхук дошел бы до самого сервиса или его
+// окружения: loopback, частные сети (RFC 1918, fc00::/7), link-local (в т.ч. метаданные
+// облака 169.254.169.254), неуказанный адрес и multicast
+func allowedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// CheckHost отклоняет URL с заведомо внутренним хостом еще при сохранении эндпоинта.
+// Имена, которые разрешаются во внутренние адреса, ловит проверка при соединении.
+func CheckHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenDestination
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil && !allowedAddr(addr) {
+		return ErrForbiddenDestination
+	}
+	return nil
+}
+
+// dialControl проверяет уже разрешенный адрес перед соединением: так DNS-имя, указывающее
+// на внутренний адрес, и перенаправления на такие адреса тоже не пройдут
+func dialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenDestination, address)
+	}
+	if !allowedAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenDestination, addrPort.Addr())
+	}
+	return nil
+}
+
+// newHTTPClient — клиент доставки, который соединяется только с внешними адресами.
+// Прокси из окружения не используется: иначе проверялся бы адрес прокси, а не подписчика.
+func newHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialControl,
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
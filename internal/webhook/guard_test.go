@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{"example.com", true},
+		{"93.184.216.34", true},
+		{"localhost", false},
+		{"api.localhost", false},
+		{"127.0.0.1", false},
+		{"10.0.0.5", false},
+		{"172.16.1.1", false},
+		{"192.168.1.10", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"[::1]", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		err := CheckHost(tt.host)
+		if (err == nil) != tt.allowed {
+			t.Errorf("CheckHost(%q) = %v, want allowed %v", tt.host, err, tt.allowed)
+		}
+	}
+}
+
+func TestDispatcherRefusesInternalAddress(t *testing.T) {
+	srv, requests := receiver(t)
+	store := &memoryStore{}
+	store.enqueue(dispatchTo(srv.URL, 0))
+
+	// Клиент по умолчанию: адрес приемника — 127.0.0.1
+	if err := NewDispatcher(store).DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(requests()); n != 0 {
+		t.Fatalf("receiver got %d requests, want none", n)
+	}
+	got := store.lastAttempt(t)
+	if got.delivered || !strings.Contains(got.lastError, ErrForbiddenDestination.Error()) {
+		t.Errorf("attempt = %+v, want refused destination", got)
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	if err := dialControl("tcp", "169.254.169.254:80", nil); !errors.Is(err, ErrForbiddenDestination) {
+		t.Errorf("dialControl(metadata) = %v, want ErrForbiddenDestination", err)
+	}
+	if err := dialControl("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("dialControl(public) = %v, want nil", err)
+	}
+}
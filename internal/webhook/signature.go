@@ -0,0 +1,77 @@
+// Package webhook подписывает и доставляет события на эндпоинты подписчиков
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader содержит время отправки и HMAC-SHA256 от "<timestamp>.<тело>":
+//
+//	X-Webhook-Signature: t=1700000000,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd
+const SignatureHeader = "X-Webhook-Signature"
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// GenerateSecret создает секрет для подписи событий эндпоинта
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", ts, computeSignature(secret, ts, body))
+}
+
+// Verify проверяет заголовок подписи на стороне получателя. tolerance ограничивает
+// возраст события и защищает от повторной отправки перехваченного запроса.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var (
+		ts        int64
+		signature string
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			ts = parsed
+		case "v1":
+			signature = value
+		}
+	}
+	if ts == 0 || signature == "" {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 && math.Abs(time.Since(time.Unix(ts, 0)).Seconds()) > tolerance.Seconds() {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	expected := computeSignature(secret, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func computeSignature(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", ts)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
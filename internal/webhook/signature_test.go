@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"type":"transfer.completed"}`)
+	now := time.Now()
+	header := Sign(secret, now, body)
+
+	if !strings.HasPrefix(header, "t=") || !strings.Contains(header, ",v1=") {
+		t.Fatalf("Sign() = %q, want t=...,v1=...", header)
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		wantErr bool
+	}{
+		{"valid", secret, header, body, false},
+		{"tampered body", secret, header, []byte(`{"type":"transfer.received"}`), true},
+		{"wrong secret", "whsec_other", header, body, true},
+		{"expired", secret, Sign(secret, now.Add(-10*time.Minute), body), body, true},
+		{"missing signature", secret, "t=1700000000", body, true},
+		{"garbage", secret, "nonsense", body, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.body, 5*time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Register создает пользователя и запоминает выданный токен
+func (c *Client) Register(ctx context.Context, req RegisterRequest) (*AuthResponse, error) {
+	var resp AuthResponse
+	if err := c.Do(ctx, http.MethodPost, "/auth/register", req, &resp); err != nil {
+		return nil, err
+	}
+	c.SetToken(resp.Token)
+	return &resp, nil
+}
+
+// Login получает токен и использует его в следующих запросах
+func (c *Client) Login(ctx context.Context, email, password string) (*AuthResponse, error) {
+	req := map[string]string{"email": email, "password": password}
+	var resp AuthResponse
+	if err := c.Do(ctx, http.MethodPost, "/auth/login", req, &resp); err != nil {
+		return nil, err
+	}
+	c.SetToken(resp.Token)
+	return &resp, nil
+}
+
+func (c *Client) Balance(ctx context.Context) (*Balance, error) {
+	var b Balance
+	if err := c.Do(ctx, http.MethodGet, "/api/balance", nil, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *Client) Deposit(ctx context.Context, amount float64) error {
+	return c.Do(ctx, http.MethodPost, "/api/deposit", map[string]float64{"amount": amount}, nil)
+}
+
+// Transfer не повторяется при сбоях сети: повтор мог бы списать деньги дважды.
+// После такой ошибки проверьте историю переводов.
+func (c *Client) Transfer(ctx context.Context, req TransferRequest) (*TransferResult, error) {
+	var res TransferResult
+	if err := c.Do(ctx, http.MethodPost, "/api/transfer", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) Transfers(ctx context.Context) ([]Transfer, error) {
+	var transfers []Transfer
+	if err := c.Do(ctx, http.MethodGet, "/api/transfers", nil, &transfers); err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (c *Client) SearchTransfers(ctx context.Context, query string) ([]Transfer, error) {
+	var transfers []Transfer
+	path := "/api/transfers/search?q=" + url.QueryEscape(query)
+	if err := c.Do(ctx, http.MethodGet, path, nil, &transfers); err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (c *Client) SetTransferTags(ctx context.Context, transferID string, tags []string) error {
+	path := "/api/transfers/" + url.PathEscape(transferID) + "/tags"
+	return c.Do(ctx, http.MethodPut, path, map[string][]string{"tags": tags}, nil)
+}
+
+// SubmitTransferBatch ставит пакет в очередь. Ошибки строк — в (*Error).Lines.
+func (c *Client) SubmitTransferBatch(ctx context.Context, req BatchTransferRequest) (*TransferBatch, error) {
+	var batch TransferBatch
+	if err := c.Do(ctx, http.MethodPost, "/api/transfers/batch", req, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// SubmitTransferBatchCSV отправляет пакет в CSV. Первая строка — заголовок с колонками
+// to_email и amount; currency, memo и reference необязательны.
+func (c *Client) SubmitTransferBatchCSV(ctx context.Context, csv []byte, mode, password string) (*TransferBatch, error) {
+	path := "/api/transfers/batch"
+	if mode != "" {
+		path += "?mode=" + url.QueryEscape(mode)
+	}
+	header := http.Header{}
+	if password != "" {
+		header.Set("X-Confirm-Password", password)
+	}
+
+	var batch TransferBatch
+	if err := c.do(ctx, http.MethodPost, path, "text/csv", csv, header, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (c *Client) TransferBatch(ctx context.Context, id string) (*TransferBatch, error) {
+	var batch TransferBatch
+	if err := c.Do(ctx, http.MethodGet, "/api/transfers/batch/"+url.PathEscape(id), nil, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (c *Client) Beneficiaries(ctx context.Context) ([]Beneficiary, error) {
+	var list []Beneficiary
+	if err := c.Do(ctx, http.MethodGet, "/api/beneficiaries", nil, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (c *Client) CreateBeneficiary(ctx context.Context, req CreateBeneficiaryRequest) (*Beneficiary, error) {
+	var b Beneficiary
+	if err := c.Do(ctx, http.MethodPost, "/api/beneficiaries", req, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *Client) DeleteBeneficiary(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/api/beneficiaries/"+url.PathEscape(id), nil, nil)
+}
+
+// ConfirmRecipient показывает замаскированное имя получателя и нужен ли step-up для суммы
+func (c *Client) ConfirmRecipient(ctx context.Context, to Recipient, amount float64, currency string) (*RecipientConfirmation, error) {
+	q := url.Values{}
+	q.Set("type", to.Type)
+	q.Set("value", to.Value)
+	if amount > 0 {
+		q.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+	}
+	if currency != "" {
+		q.Set("currency", currency)
+	}
+
+	var conf RecipientConfirmation
+	if err := c.Do(ctx, http.MethodGet, "/api/recipients/confirm?"+q.Encode(), nil, &conf); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+func (c *Client) AccountStatusHistory(ctx context.Context) ([]AccountStatusChange, error) {
+	var history []AccountStatusChange
+	if err := c.Do(ctx, http.MethodGet, "/api/account/status-history", nil, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (c *Client) FreezeAccount(ctx context.Context, reason string) (*Account, error) {
+	return c.accountAction(ctx, "/api/account/freeze", map[string]string{"reason": reason})
+}
+
+func (c *Client) UnfreezeAccount(ctx context.Context, reason string) (*Account, error) {
+	return c.accountAction(ctx, "/api/account/unfreeze", map[string]string{"reason": reason})
+}
+
+func (c *Client) CloseAccount(ctx context.Context, req CloseAccountRequest) (*Account, error) {
+	return c.accountAction(ctx, "/api/account/close", req)
+}
+
+func (c *Client) accountAction(ctx context.Context, path string, body interface{}) (*Account, error) {
+	var a Account
+	if err := c.Do(ctx, http.MethodPost, path, body, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
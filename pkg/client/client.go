@@ -0,0 +1,235 @@
+// Package client — Go-клиент REST API сервиса переводов (см. /openapi.json).
+// Хранит JWT после Login/Register и повторяет запросы при временных сбоях.
+//
+//	c, _ := client.New("https://api.example.com")
+//	if _, err := c.Login(ctx, "user@example.com", "secret"); err != nil { ... }
+//	balance, err := c.Balance(ctx)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const userAgent = "money-transfer-client/1"
+
+// RetryPolicy задает повторы. Повторяются сетевые ошибки и ответы 502/503/504 —
+// только для идемпотентных методов, чтобы не отправить перевод дважды, — и 429 для любых:
+// такой запрос сервер отклонил, не выполняя.
+type RetryPolicy struct {
+	MaxAttempts int           // Всего попыток, включая первую; 1 — без повторов
+	BaseDelay   time.Duration // Пауза перед первым повтором, дальше удваивается
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+type Client struct {
+	baseURL *url.URL
+	http    *http.Client
+	retry   RetryPolicy
+
+	mu    sync.RWMutex
+	token string
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithToken задает уже полученный JWT
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base URL must be absolute: %q", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	c := &Client{
+		baseURL: u,
+		http:    &http.Client{Timeout: 30 * time.Second},
+		retry:   DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	c.token = token
+	c.mu.Unlock()
+}
+
+// Do выполняет запрос к пути API (например, "/api/escrow") и декодирует JSON-ответ в out.
+// body кодируется в JSON; nil — без тела. Ошибки API возвращаются как *Error.
+func (c *Client) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+	return c.do(ctx, method, path, "application/json", payload, nil, out)
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, payload []byte, header http.Header, out interface{}) error {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, path, contentType, payload, header)
+		if err != nil {
+			if ctx.Err() != nil || !idempotent(method) || attempt >= c.retry.MaxAttempts {
+				return err
+			}
+			if err := c.wait(ctx, attempt, 0); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if retryable(method, resp.StatusCode) && attempt < c.retry.MaxAttempts {
+			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			drain(resp)
+			if err := c.wait(ctx, attempt, retryAfter); err != nil {
+				return err
+			}
+			continue
+		}
+
+		return decode(resp, out)
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path, contentType string, payload []byte, header http.Header) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	req.Header.Set("User-Agent", userAgent)
+	if token := c.Token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.http.Do(req)
+}
+
+// wait ждет перед повтором: экспоненциально с джиттером или столько, сколько попросил сервер
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = c.retry.BaseDelay << (attempt - 1)
+		if c.retry.MaxDelay > 0 && delay > c.retry.MaxDelay {
+			delay = c.retry.MaxDelay
+		}
+		// Джиттер разводит повторы клиентов, упавших одновременно
+		if delay > 0 {
+			delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		}
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(method)
+	}
+	return false
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func decode(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return parseError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		drain(resp)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// drain дочитывает тело, чтобы соединение вернулось в пул
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
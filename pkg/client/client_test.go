@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetry — политика тестов: те же правила повторов без реальных пауз
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+// flakyServer отвечает статусами из statuses по очереди, затем — 200 с body
+func flakyServer(t *testing.T, statuses []int, body interface{}) (*Client, *int32) {
+	t.Helper()
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n <= len(statuses) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(statuses[n-1])
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": statuses[n-1], "code": "temporary"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithRetryPolicy(fastRetry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, &calls
+}
+
+func TestGetRetriesOnUnavailable(t *testing.T) {
+	c, calls := flakyServer(t, []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}, Balance{Balance: 100})
+
+	b, err := c.Balance(context.Background())
+	if err != nil {
+		t.Fatalf("Balance() error = %v", err)
+	}
+	if b.Balance != 100 {
+		t.Errorf("Balance = %v, want 100", b.Balance)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestGetStopsAfterMaxAttempts(t *testing.T) {
+	c, calls := flakyServer(t, []int{503, 503, 503, 503}, Balance{})
+
+	_, err := c.Balance(context.Background())
+	if apiErr, ok := err.(*Error); !ok || apiErr.Status != http.StatusServiceUnavailable {
+		t.Fatalf("Balance() error = %v, want *Error with status 503", err)
+	}
+	if got := atomic.LoadInt32(calls); got != int32(fastRetry.MaxAttempts) {
+		t.Errorf("calls = %d, want %d", got, fastRetry.MaxAttempts)
+	}
+}
+
+func TestPostNotRetriedOnUnavailable(t *testing.T) {
+	c, calls := flakyServer(t, []int{http.StatusServiceUnavailable}, TransferResult{TransferID: "t1"})
+
+	_, err := c.Transfer(context.Background(), TransferRequest{Amount: 10, Currency: "RUB"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.Status != http.StatusServiceUnavailable {
+		t.Fatalf("Transfer() error = %v, want *Error with status 503", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1: a POST must not be repeated", got)
+	}
+}
+
+func TestPostRetriedOnTooManyRequests(t *testing.T) {
+	c, calls := flakyServer(t, []int{http.StatusTooManyRequests}, TransferResult{TransferID: "t1"})
+
+	res, err := c.Transfer(context.Background(), TransferRequest{Amount: 10, Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if res.TransferID != "t1" {
+		t.Errorf("TransferID = %q, want t1", res.TransferID)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("2"); got != 2*time.Second {
+		t.Errorf("parseRetryAfter(2) = %v, want 2s", got)
+	}
+	if got := parseRetryAfter("soon"); got != 0 {
+		t.Errorf("parseRetryAfter(soon) = %v, want 0", got)
+	}
+}
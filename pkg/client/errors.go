@@ -0,0 +1,98 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// Error — ответ API с ошибкой (RFC 7807). Code стабилен, по нему и стоит ветвиться:
+// insufficient_funds, account_frozen, limit_exceeded, validation_failed...
+type Error struct {
+	Status    int          `json:"status"`
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Detail    string       `json:"detail"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id"`
+	Errors    []FieldError `json:"-"`
+	// Lines — ошибки по строкам пакета переводов (code batch_invalid)
+	Lines []BatchLineError `json:"-"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+type BatchLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("api error %d", e.Status)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.RequestID != "" {
+		msg += " (request_id " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// ErrorCode возвращает код ошибки API или "", если err пришла не от API
+func ErrorCode(err error) string {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
+
+func parseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := &Error{
+		Status:    resp.StatusCode,
+		Title:     http.StatusText(resp.StatusCode),
+		RequestID: resp.Header.Get("X-Request-ID"),
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/problem+json" {
+		// Ответ не от обработчика API (прокси, балансировщик)
+		apiErr.Detail = string(body)
+		return apiErr
+	}
+
+	var raw struct {
+		Error
+		Errors json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		apiErr.Detail = string(body)
+		return apiErr
+	}
+	requestID := apiErr.RequestID
+	*apiErr = raw.Error
+	apiErr.Status = resp.StatusCode
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = requestID
+	}
+	if len(raw.Errors) > 0 {
+		if apiErr.Code == "batch_invalid" {
+			json.Unmarshal(raw.Errors, &apiErr.Lines)
+		} else {
+			json.Unmarshal(raw.Errors, &apiErr.Errors)
+		}
+	}
+	return apiErr
+}
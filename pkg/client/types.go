@@ -0,0 +1,172 @@
+package client
+
+import "time"
+
+// Типы повторяют схемы из openapi.json. Для остальных операций — Client.Do со своими типами.
+
+type User struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	FullName  string    `json:"full_name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuthResponse struct {
+	Token string `json:"token"`
+	User  User   `json:"user"`
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	FullName string `json:"full_name"`
+}
+
+type Balance struct {
+	AccountID     string  `json:"account_id"`
+	AccountNumber string  `json:"account_number"`
+	Balance       float64 `json:"balance"`
+	Available     float64 `json:"available_balance"`
+	Held          float64 `json:"held_balance"`
+	Currency      string  `json:"currency"`
+}
+
+type Account struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"user_id"`
+	Number      string  `json:"account_number"`
+	Balance     float64 `json:"balance"`
+	HeldBalance float64 `json:"held_balance"`
+	Status      string  `json:"status"`
+}
+
+type AccountStatusChange struct {
+	ID        string    `json:"id"`
+	AccountID string    `json:"account_id"`
+	OldStatus string    `json:"old_status"`
+	NewStatus string    `json:"new_status"`
+	ChangedBy string    `json:"changed_by"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CloseAccountRequest struct {
+	Reason       string `json:"reason"`
+	SweepToEmail string `json:"sweep_to_email,omitempty"`
+	Password     string `json:"password,omitempty"`
+}
+
+// Типы получателя
+const (
+	RecipientEmail         = "email"
+	RecipientPhone         = "phone"
+	RecipientUsername      = "username"
+	RecipientAccountNumber = "account_number"
+)
+
+type Recipient struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// TransferRequest — нужен либо To, либо BeneficiaryID
+type TransferRequest struct {
+	To            *Recipient `json:"to,omitempty"`
+	BeneficiaryID string     `json:"beneficiary_id,omitempty"`
+	Amount        float64    `json:"amount"`
+	Currency      string     `json:"currency"`
+	Memo          string     `json:"memo,omitempty"`
+	Reference     string     `json:"reference,omitempty"`
+	// Password — подтверждение для переводов, требующих step-up (code step_up_required)
+	Password string `json:"password,omitempty"`
+}
+
+type TransferResult struct {
+	Message    string `json:"message"`
+	TransferID string `json:"transfer_id"`
+}
+
+type Transfer struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from_account_id"`
+	To        string    `json:"to_account_id"`
+	FromEmail string    `json:"from_email"`
+	ToEmail   string    `json:"to_email"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Memo      string    `json:"memo"`
+	Reference string    `json:"reference"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Режимы пакета переводов
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+)
+
+type BatchTransferLine struct {
+	ToEmail   string  `json:"to_email"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency,omitempty"`
+	Memo      string  `json:"memo,omitempty"`
+	Reference string  `json:"reference,omitempty"`
+}
+
+type BatchTransferRequest struct {
+	Mode     string              `json:"mode,omitempty"`
+	Password string              `json:"password,omitempty"`
+	Items    []BatchTransferLine `json:"items"`
+}
+
+type TransferBatch struct {
+	ID          string              `json:"id"`
+	UserID      string              `json:"user_id"`
+	AccountID   string              `json:"account_id"`
+	Mode        string              `json:"mode"`
+	Status      string              `json:"status"`
+	TotalLines  int                 `json:"total_lines"`
+	Succeeded   int                 `json:"succeeded"`
+	Failed      int                 `json:"failed"`
+	CreatedAt   time.Time           `json:"created_at"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+	Items       []TransferBatchItem `json:"items,omitempty"`
+}
+
+type TransferBatchItem struct {
+	Line       int     `json:"line"`
+	ToEmail    string  `json:"to_email"`
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`
+	Memo       string  `json:"memo,omitempty"`
+	Reference  string  `json:"reference,omitempty"`
+	Status     string  `json:"status"`
+	TransferID string  `json:"transfer_id,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type Beneficiary struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	AccountID  string    `json:"account_id"`
+	Email      string    `json:"email"`
+	Nickname   string    `json:"nickname"`
+	IsFavorite bool      `json:"is_favorite"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateBeneficiaryRequest struct {
+	Email      string `json:"email"`
+	Nickname   string `json:"nickname"`
+	IsFavorite bool   `json:"is_favorite"`
+}
+
+type RecipientConfirmation struct {
+	Type           string `json:"type"`
+	Value          string `json:"value"`
+	MaskedName     string `json:"masked_name"`
+	BeneficiaryID  string `json:"beneficiary_id,omitempty"`
+	StepUpRequired bool   `json:"step_up_required"`
+}
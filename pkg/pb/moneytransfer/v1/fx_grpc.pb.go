@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: moneytransfer/v1/fx.proto
+
+package moneytransferv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FXService_ListRates_FullMethodName = "/moneytransfer.v1.FXService/ListRates"
+	FXService_GetRate_FullMethodName   = "/moneytransfer.v1.FXService/GetRate"
+	FXService_Convert_FullMethodName   = "/moneytransfer.v1.FXService/Convert"
+)
+
+// FXServiceClient is the client API for FXService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FXService exposes the exchange rates used for transfers. Rates are to RUB.
+type FXServiceClient interface {
+	ListRates(ctx context.Context, in *ListRatesRequest, opts ...grpc.CallOption) (*ListRatesResponse, error)
+	GetRate(ctx context.Context, in *GetRateRequest, opts ...grpc.CallOption) (*GetRateResponse, error)
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error)
+}
+
+type fXServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFXServiceClient(cc grpc.ClientConnInterface) FXServiceClient {
+	return &fXServiceClient{cc}
+}
+
+func (c *fXServiceClient) ListRates(ctx context.Context, in *ListRatesRequest, opts ...grpc.CallOption) (*ListRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRatesResponse)
+	err := c.cc.Invoke(ctx, FXService_ListRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fXServiceClient) GetRate(ctx context.Context, in *GetRateRequest, opts ...grpc.CallOption) (*GetRateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRateResponse)
+	err := c.cc.Invoke(ctx, FXService_GetRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fXServiceClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConvertResponse)
+	err := c.cc.Invoke(ctx, FXService_Convert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FXServiceServer is the server API for FXService service.
+// All implementations must embed UnimplementedFXServiceServer
+// for forward compatibility.
+//
+// FXService exposes the exchange rates used for transfers. Rates are to RUB.
+type FXServiceServer interface {
+	ListRates(context.Context, *ListRatesRequest) (*ListRatesResponse, error)
+	GetRate(context.Context, *GetRateRequest) (*GetRateResponse, error)
+	Convert(context.Context, *ConvertRequest) (*ConvertResponse, error)
+	mustEmbedUnimplementedFXServiceServer()
+}
+
+// UnimplementedFXServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFXServiceServer struct{}
+
+func (UnimplementedFXServiceServer) ListRates(context.Context, *ListRatesRequest) (*ListRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRates not implemented")
+}
+func (UnimplementedFXServiceServer) GetRate(context.Context, *GetRateRequest) (*GetRateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRate not implemented")
+}
+func (UnimplementedFXServiceServer) Convert(context.Context, *ConvertRequest) (*ConvertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedFXServiceServer) mustEmbedUnimplementedFXServiceServer() {}
+func (UnimplementedFXServiceServer) testEmbeddedByValue()                   {}
+
+// UnsafeFXServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FXServiceServer will
+// result in compilation errors.
+type UnsafeFXServiceServer interface {
+	mustEmbedUnimplementedFXServiceServer()
+}
+
+func RegisterFXServiceServer(s grpc.ServiceRegistrar, srv FXServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFXServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FXService_ServiceDesc, srv)
+}
+
+func _FXService_ListRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FXServiceServer).ListRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FXService_ListRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FXServiceServer).ListRates(ctx, req.(*ListRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FXService_GetRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FXServiceServer).GetRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FXService_GetRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FXServiceServer).GetRate(ctx, req.(*GetRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FXService_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FXServiceServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FXService_Convert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FXServiceServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FXService_ServiceDesc is the grpc.ServiceDesc for FXService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FXService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "moneytransfer.v1.FXService",
+	HandlerType: (*FXServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRates",
+			Handler:    _FXService_ListRates_Handler,
+		},
+		{
+			MethodName: "GetRate",
+			Handler:    _FXService_GetRate_Handler,
+		},
+		{
+			MethodName: "Convert",
+			Handler:    _FXService_Convert_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "moneytransfer/v1/fx.proto",
+}
@@ -30,8 +30,6 @@ async function depositMoney(event) {
         
         alert('Счет успешно пополнен!');
         document.getElementById('deposit-amount').value = '';
-        
-        loadUserData();
     } catch (error) {
         alert('Ошибка пополнения: ' + error.message);
     }
@@ -53,6 +51,7 @@ function showDashboard() {
     document.getElementById('auth-buttons').style.display = 'none';
     document.getElementById('user-info').style.display = 'block';
     document.getElementById('user-name').textContent = currentUser.full_name;
+    connectUpdates();
 }
 
 function showAuthForms() {
@@ -63,6 +62,53 @@ function showAuthForms() {
 }
 
 
+// Тексты ошибок по стабильным кодам из problem+json. Если кода нет в списке,
+// показываем detail от сервера.
+const ERROR_MESSAGES = {
+    insufficient_funds: 'Недостаточно средств на счете',
+    account_not_found: 'Счет не найден',
+    account_frozen: 'Счет заморожен',
+    account_closed: 'Счет закрыт',
+    debit_blocked: 'Списания со счета заблокированы',
+    credit_blocked: 'Зачисления на счет заблокированы',
+    limit_exceeded: 'Превышен дневной лимит переводов',
+    invalid_amount: 'Сумма должна быть больше нуля',
+    unsupported_currency: 'Валюта не поддерживается',
+    step_up_required: 'Подтвердите перевод паролем',
+    invalid_password: 'Неверный пароль',
+    beneficiary_not_found: 'Контакт не найден',
+    invalid_alias: 'Некорректный адрес получателя',
+    alias_taken: 'Этот псевдоним уже занят',
+    validation_failed: 'Проверьте введенные данные',
+    invalid_json: 'Некорректный запрос',
+    body_too_large: 'Слишком большой запрос',
+    unauthorized: 'Требуется вход в систему',
+    internal_error: 'Внутренняя ошибка сервера, попробуйте позже',
+};
+
+async function problemError(response) {
+    let problem = null;
+    const contentType = response.headers.get('Content-Type') || '';
+    if (contentType.includes('json')) {
+        problem = await response.json().catch(() => null);
+    }
+    if (!problem) {
+        const text = await response.text().catch(() => '');
+        return new Error(text || `HTTP error ${response.status}`);
+    }
+
+    let message = ERROR_MESSAGES[problem.code] || problem.detail || problem.title;
+    // Ошибки по полям: "amount must be greater than 0"
+    if (problem.code === 'validation_failed' && Array.isArray(problem.errors)) {
+        message += ': ' + problem.errors.map(e => `${e.field} ${e.message}`).join('; ');
+    }
+    const error = new Error(message);
+    error.code = problem.code;
+    error.status = problem.status;
+    error.problem = problem;
+    return error;
+}
+
 // И обновите функцию apiRequest:
 async function apiRequest(url, options = {}) {
     const fullUrl = url.startsWith('http') ? url : `http://localhost:8080${url}`;
@@ -83,8 +129,7 @@ async function apiRequest(url, options = {}) {
         });
         
         if (!response.ok) {
-            const errorText = await response.text();
-            throw new Error(errorText || `HTTP error ${response.status}`);
+            throw await problemError(response);
         }
         
         return response.json();
@@ -153,6 +198,7 @@ async function login(event) {
 }
 
 function logout() {
+    disconnectUpdates();
     authToken = null;
     currentUser = null;
     localStorage.removeItem('authToken');
@@ -164,38 +210,181 @@ function logout() {
 async function loadUserData() {
     try {
         const balanceData = await apiRequest('/api/balance');
-        document.getElementById('balance-amount').textContent = 
-            `${balanceData.balance.toFixed(2)} ${balanceData.currency}`;
-        
-        const transfers = await apiRequest('/api/transfers');
-        renderTransfers(transfers);
+        renderBalance(balanceData);
+        await loadTransfers();
     } catch (error) {
         console.error('Ошибка загрузки данных:', error);
     }
 }
 
+async function loadTransfers() {
+    const transfers = await apiRequest('/api/transfers');
+    renderTransfers(transfers);
+}
+
+function renderBalance(balanceData) {
+    let balanceText = `${balanceData.available_balance.toFixed(2)} ${balanceData.currency}`;
+    if (balanceData.held_balance > 0) {
+        balanceText += ` (удержано ${balanceData.held_balance.toFixed(2)})`;
+    }
+    document.getElementById('balance-amount').textContent = balanceText;
+    document.getElementById('account-number').textContent =
+        `Счет № ${formatAccountNumber(balanceData.account_number)}`;
+}
+
+// Обновления в реальном времени (Server-Sent Events). EventSource не умеет передавать
+// заголовок Authorization, поэтому поток читаем через fetch.
+let updatesController = null;
+
+async function connectUpdates() {
+    disconnectUpdates();
+    const controller = new AbortController();
+    updatesController = controller;
+
+    try {
+        const response = await fetch('/api/updates', {
+            headers: { 'Authorization': `Bearer ${authToken}` },
+            signal: controller.signal,
+        });
+        if (!response.ok) {
+            throw new Error(`HTTP error ${response.status}`);
+        }
+
+        const reader = response.body.getReader();
+        const decoder = new TextDecoder();
+        let buffer = '';
+        while (true) {
+            const { value, done } = await reader.read();
+            if (done) {
+                break;
+            }
+            buffer += decoder.decode(value, { stream: true });
+
+            let boundary;
+            while ((boundary = buffer.indexOf('\n\n')) !== -1) {
+                handleUpdate(buffer.slice(0, boundary));
+                buffer = buffer.slice(boundary + 2);
+            }
+        }
+    } catch (error) {
+        if (controller.signal.aborted) {
+            return;
+        }
+        console.error('Поток обновлений прерван:', error);
+    }
+
+    // Переподключаемся, если пользователь все еще в системе
+    if (updatesController === controller && authToken) {
+        setTimeout(() => {
+            if (updatesController === controller && authToken) {
+                connectUpdates();
+            }
+        }, 3000);
+    }
+}
+
+function disconnectUpdates() {
+    if (updatesController) {
+        updatesController.abort();
+        updatesController = null;
+    }
+}
+
+function handleUpdate(chunk) {
+    let event = 'message';
+    let data = '';
+    for (const line of chunk.split('\n')) {
+        if (line.startsWith('event: ')) {
+            event = line.slice(7);
+        } else if (line.startsWith('data: ')) {
+            data += line.slice(6);
+        }
+    }
+    if (!data) {
+        return;
+    }
+
+    const payload = JSON.parse(data);
+    switch (event) {
+        case 'balance':
+            renderBalance(payload);
+            break;
+        case 'transfer.received':
+            showNotice(`Поступил перевод ${payload.amount.toFixed(2)} ${payload.currency} от ${payload.counterparty}`);
+            loadTransfers().catch(console.error);
+            break;
+        case 'transfer.sent':
+            loadTransfers().catch(console.error);
+            break;
+        case 'account.status':
+            showNotice(`Статус счета изменен: ${payload.new_status}`);
+            break;
+    }
+}
+
+function showNotice(text) {
+    const notice = document.createElement('div');
+    notice.className = 'notice';
+    notice.textContent = text;
+    document.body.appendChild(notice);
+    setTimeout(() => notice.remove(), 5000);
+}
+
+// Определяем тип псевдонима по введенному значению
+function parseRecipient(input) {
+    const value = input.trim();
+    const digits = value.replace(/[\s-]/g, '');
+    if (value.includes('@') && !value.startsWith('@')) {
+        return { type: 'email', value };
+    }
+    if (/^\d{20}$/.test(digits)) {
+        return { type: 'account_number', value: digits };
+    }
+    if (/^\+?\d[\d\s()-]{9,}$/.test(value)) {
+        return { type: 'phone', value };
+    }
+    return { type: 'username', value };
+}
+
 async function transferMoney(event) {
     event.preventDefault();
     
-    const recipientEmail = document.getElementById('recipient-email').value;
+    const to = parseRecipient(document.getElementById('recipient-email').value);
     const amount = parseFloat(document.getElementById('transfer-amount').value);
     const currency = document.getElementById('transfer-currency').value;
+    const memo = document.getElementById('transfer-memo').value;
     
     try {
+        // Показываем замаскированное имя получателя, чтобы поймать опечатку в адресе
+        const params = new URLSearchParams({ type: to.type, value: to.value, amount, currency });
+        const recipient = await apiRequest(`/api/recipients/confirm?${params}`);
+        if (!confirm(`Перевести ${amount} ${currency} получателю ${recipient.masked_name}?`)) {
+            return;
+        }
+
+        const body = { amount, currency, memo };
+        if (recipient.beneficiary_id) {
+            body.beneficiary_id = recipient.beneficiary_id;
+        } else {
+            body.to = { type: recipient.type, value: recipient.value };
+        }
+        if (recipient.step_up_required) {
+            const password = prompt('Для перевода новому получателю подтвердите пароль');
+            if (!password) {
+                return;
+            }
+            body.password = password;
+        }
+
         await apiRequest('/api/transfer', {
             method: 'POST',
-            body: JSON.stringify({
-                to_email: recipientEmail,
-                amount,
-                currency
-            }),
+            body: JSON.stringify(body),
         });
         
         alert('Перевод выполнен успешно!');
         document.getElementById('recipient-email').value = '';
         document.getElementById('transfer-amount').value = '';
-        
-        loadUserData();
+        document.getElementById('transfer-memo').value = '';
     } catch (error) {
         alert('Ошибка перевода: ' + error.message);
     }
@@ -227,9 +416,24 @@ function renderTransfers(transfers) {
             <div class="${amountClass}">
                 ${amountPrefix}${transfer.amount} ${transfer.currency}
             </div>
+            ${transfer.memo ? `<div class="transfer-memo">${escapeHtml(transfer.memo)}</div>` : ''}
             <div>${new Date(transfer.created_at).toLocaleDateString()}</div>
         `;
         
         container.appendChild(div);
     });
-}
\ No newline at end of file
+}
+
+function escapeHtml(text) {
+    const div = document.createElement('div');
+    div.textContent = text;
+    return div.innerHTML;
+}
+
+// 40817 810 0 1234 5678901
+function formatAccountNumber(number) {
+    if (!number || number.length !== 20) {
+        return number || '';
+    }
+    return [number.slice(0, 5), number.slice(5, 8), number.slice(8, 9), number.slice(9, 13), number.slice(13)].join(' ');
+}